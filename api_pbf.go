@@ -0,0 +1,561 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/angelodlfrtr/valhalla-http-client-go/pbf"
+)
+
+// pbfContentType content type of valhalla pbf responses
+const pbfContentType = "application/x-protobuf"
+
+// errPBFNoMatrix is returned when decoding a matrix pbf response without matrix
+var errPBFNoMatrix = errors.New("no matrix in pbf response")
+
+// pbfUnmarshaler is implemented by outputs which can be decoded from a valhalla pbf response
+type pbfUnmarshaler interface {
+	unmarshalPBF(data []byte) error
+}
+
+// Field numbers of the valhalla api.proto messages decoded by the client.
+// Only the fields having an equivalent in the json outputs are decoded, others are skipped.
+// See https://github.com/valhalla/valhalla/tree/master/proto
+const (
+	pbfAPIOptions    = 1
+	pbfAPIDirections = 3
+	pbfAPIMatrix     = 5
+
+	pbfOptionsUnits = 1
+	pbfOptionsID    = 5
+
+	pbfDirectionsRoutes = 1
+	pbfRouteLegs        = 1
+
+	pbfLegLocation = 4
+	pbfLegSummary  = 5
+	pbfLegManeuver = 6
+	pbfLegShape    = 7
+
+	pbfSummaryLength              = 1
+	pbfSummaryTime                = 2
+	pbfSummaryBBox                = 3
+	pbfSummaryHasTimeRestrictions = 4
+
+	pbfBBoxMin = 1
+	pbfBBoxMax = 2
+
+	pbfLatLngLat = 1
+	pbfLatLngLng = 2
+
+	pbfLocationLatLng       = 1
+	pbfLocationType         = 2
+	pbfLocationHeading      = 3
+	pbfLocationName         = 4
+	pbfLocationStreet       = 5
+	pbfLocationDateTime     = 12
+	pbfLocationSideOfStreet = 13
+
+	pbfStreetNameValue = 1
+
+	pbfManeuverType                                = 1
+	pbfManeuverTextInstruction                     = 2
+	pbfManeuverStreetName                          = 3
+	pbfManeuverLength                              = 4
+	pbfManeuverTime                                = 5
+	pbfManeuverBeginShapeIndex                     = 8
+	pbfManeuverEndShapeIndex                       = 9
+	pbfManeuverPortionsToll                        = 10
+	pbfManeuverPortionsUnpaved                     = 11
+	pbfManeuverVerbalTransitionAlertInstruction    = 12
+	pbfManeuverVerbalPreTransitionInstruction      = 13
+	pbfManeuverVerbalPostTransitionInstruction     = 14
+	pbfManeuverBeginStreetName                     = 15
+	pbfManeuverRoundaboutExitCount                 = 17
+	pbfManeuverDepartInstruction                   = 18
+	pbfManeuverVerbalDepartInstruction             = 19
+	pbfManeuverArriveInstruction                   = 20
+	pbfManeuverVerbalArriveInstruction             = 21
+	pbfManeuverVerbalMultiCue                      = 23
+	pbfManeuverTravelMode                          = 24
+	pbfManeuverVehicleType                         = 25
+	pbfManeuverPedestrianType                      = 26
+	pbfManeuverBicycleType                         = 27
+	pbfManeuverTransitType                         = 28
+	pbfManeuverVerbalSuccinctTransitionInstruction = 37
+	pbfManeuverPortionsFerry                       = 40
+
+	pbfMatrixDistances       = 2
+	pbfMatrixTimes           = 3
+	pbfMatrixFromIndices     = 4
+	pbfMatrixToIndices       = 5
+	pbfMatrixDateTimes       = 6
+	pbfMatrixTimeZoneOffsets = 8
+	pbfMatrixTimeZoneNames   = 9
+)
+
+// Values of the api.proto enums, indexed by enum value
+var (
+	pbfUnits         = []string{"kilometers", "miles"}
+	pbfLocationTypes = []string{RouteInputLocationTypeBreak, RouteInputLocationTypeThrough, RouteInputLocationTypeVia, RouteInputLocationTypeBreakThrough}
+	pbfSidesOfStreet = []string{"", "left", "right"}
+
+	pbfTravelModes = []TravelMode{TravelModeDrive, TravelModePedestrian, TravelModeBicycle, TravelModeTransit}
+
+	// pbfTravelTypes travel types indexed by travel mode then by vehicle, pedestrian, bicycle or transit type
+	pbfTravelTypes = [][]TravelType{
+		{TravelTypeCar, TravelTypeMotorcycle, TravelTypeBus, TravelTypeTractorTrailer, TravelTypeMotorScooter},
+		{TravelTypeFoot, TravelTypeWheelchair, TravelTypeSegway},
+		{TravelTypeRoad, TravelTypeCross, TravelTypeHybrid, TravelTypeMountain},
+		{TravelTypeTram, TravelTypeMetro, TravelTypeRail, TravelTypeBus, TravelTypeFerry, TravelTypeCableCar, TravelTypeGondola, TravelTypeFunicular},
+	}
+)
+
+// pbfMatrixUnreachable valhalla sets the time of unreachable matrix cells to its
+// matrix max cost, kMaxCost of thor/matrix_common.h
+const pbfMatrixUnreachable float32 = 99999999.9999
+
+// metersPerMile used to convert matrix distances, which are in meters in pbf responses
+const metersPerMile = 1609.344
+
+// pbfEnum returns the name of enum value v, nil if v is out of names
+func pbfEnum(names []string, v uint64) *string {
+	if v >= uint64(len(names)) || names[v] == "" {
+		return nil
+	}
+
+	return &names[v]
+}
+
+// pbfOptions decoded fields of the api.proto Options message
+type pbfOptions struct {
+	units uint64
+	id    *string
+}
+
+func decodePBFOptions(r *pbf.Reader) *pbfOptions {
+	options := &pbfOptions{}
+	for r.Next() {
+		switch r.Field() {
+		case pbfOptionsUnits:
+			options.units = r.Uint64()
+		case pbfOptionsID:
+			id := r.String()
+			options.id = &id
+		default:
+			r.Skip()
+		}
+	}
+
+	return options
+}
+
+// unmarshalPBF decodes the first route of the directions of a valhalla pbf response.
+// Maneuver signs and transit info are not decoded.
+func (output *RouteOutput) unmarshalPBF(data []byte) error {
+	r := pbf.NewReader(data)
+	for r.Next() {
+		switch r.Field() {
+		case pbfAPIOptions:
+			output.ID = decodePBFOptions(r.Message()).id
+		case pbfAPIDirections:
+			directions := r.Message()
+			for directions.Next() {
+				// First route is the trip, others are alternates
+				if directions.Field() != pbfDirectionsRoutes || output.Trip != nil {
+					directions.Skip()
+					continue
+				}
+
+				output.Trip = decodePBFTrip(directions.Message())
+			}
+		default:
+			r.Skip()
+		}
+	}
+
+	return r.Err()
+}
+
+func decodePBFTrip(r *pbf.Reader) *RouteOutputTrip {
+	trip := &RouteOutputTrip{Summary: &RouteOutputTripSummary{}}
+
+	for r.Next() {
+		if r.Field() != pbfRouteLegs {
+			r.Skip()
+			continue
+		}
+
+		leg, locations := decodePBFLeg(r.Message())
+		trip.Legs = append(trip.Legs, leg)
+
+		// Last location of a leg is the first of the next one
+		if len(trip.Locations) > 0 && len(locations) > 0 {
+			locations = locations[1:]
+		}
+
+		trip.Locations = append(trip.Locations, locations...)
+		mergeTripSummary(trip.Summary, leg.Summary)
+	}
+
+	return trip
+}
+
+// mergeTripSummary adds leg summary to trip summary
+func mergeTripSummary(trip *RouteOutputTripSummary, leg *RouteOutputTripSummary) {
+	if leg == nil {
+		return
+	}
+
+	addFloat := func(dst **float64, value *float64) {
+		if value == nil {
+			return
+		}
+
+		sum := *value
+		if *dst != nil {
+			sum += **dst
+		}
+
+		*dst = &sum
+	}
+
+	boundFloat := func(dst **float64, value *float64, less bool) {
+		if value != nil && (*dst == nil || (*value < **dst) == less) {
+			v := *value
+			*dst = &v
+		}
+	}
+
+	addFloat(&trip.Time, leg.Time)
+	addFloat(&trip.Length, leg.Length)
+	boundFloat(&trip.MinLat, leg.MinLat, true)
+	boundFloat(&trip.MinLon, leg.MinLon, true)
+	boundFloat(&trip.MaxLat, leg.MaxLat, false)
+	boundFloat(&trip.MaxLon, leg.MaxLon, false)
+
+	if leg.HasTimeRestrictions != nil && (trip.HasTimeRestrictions == nil || *leg.HasTimeRestrictions) {
+		v := *leg.HasTimeRestrictions
+		trip.HasTimeRestrictions = &v
+	}
+}
+
+func decodePBFLeg(r *pbf.Reader) (*RouteOutputLeg, []*RouteLocation) {
+	leg := &RouteOutputLeg{}
+	locations := []*RouteLocation{}
+
+	for r.Next() {
+		switch r.Field() {
+		case pbfLegLocation:
+			locations = append(locations, decodePBFLocation(r.Message()))
+		case pbfLegSummary:
+			leg.Summary = decodePBFSummary(r.Message())
+		case pbfLegManeuver:
+			leg.Maneuvers = append(leg.Maneuvers, decodePBFManeuver(r.Message()))
+		case pbfLegShape:
+			shape := r.String()
+			leg.Shape = &shape
+		default:
+			r.Skip()
+		}
+	}
+
+	return leg, locations
+}
+
+func decodePBFLatLng(r *pbf.Reader) (lat, lon *float64) {
+	latValue, lonValue := 0.0, 0.0
+	for r.Next() {
+		switch r.Field() {
+		case pbfLatLngLat:
+			latValue = r.Double()
+		case pbfLatLngLng:
+			lonValue = r.Double()
+		default:
+			r.Skip()
+		}
+	}
+
+	return &latValue, &lonValue
+}
+
+func decodePBFLocation(r *pbf.Reader) *RouteLocation {
+	// proto3 omits zero values, json responses always have the location type
+	location := &RouteLocation{Type: pbfEnum(pbfLocationTypes, 0)}
+	for r.Next() {
+		switch r.Field() {
+		case pbfLocationLatLng:
+			location.Lat, location.Lon = decodePBFLatLng(r.Message())
+		case pbfLocationType:
+			location.Type = pbfEnum(pbfLocationTypes, r.Uint64())
+		case pbfLocationHeading:
+			heading := float32(r.Uint32())
+			location.Heading = &heading
+		case pbfLocationName:
+			name := r.String()
+			location.Name = &name
+		case pbfLocationStreet:
+			street := r.String()
+			location.Street = &street
+		case pbfLocationDateTime:
+			dateTime := r.String()
+			location.DateTime = &dateTime
+		case pbfLocationSideOfStreet:
+			location.SideOfStreet = pbfEnum(pbfSidesOfStreet, r.Uint64())
+		default:
+			r.Skip()
+		}
+	}
+
+	return location
+}
+
+func decodePBFSummary(r *pbf.Reader) *RouteOutputTripSummary {
+	// proto3 omits zero values, json responses always have them
+	summary := &RouteOutputTripSummary{Time: new(float64), Length: new(float64), HasTimeRestrictions: new(bool)}
+	for r.Next() {
+		switch r.Field() {
+		case pbfSummaryLength:
+			length := float64(r.Float())
+			summary.Length = &length
+		case pbfSummaryTime:
+			t := r.Double()
+			summary.Time = &t
+		case pbfSummaryHasTimeRestrictions:
+			v := r.Bool()
+			summary.HasTimeRestrictions = &v
+		case pbfSummaryBBox:
+			bbox := r.Message()
+			for bbox.Next() {
+				switch bbox.Field() {
+				case pbfBBoxMin:
+					summary.MinLat, summary.MinLon = decodePBFLatLng(bbox.Message())
+				case pbfBBoxMax:
+					summary.MaxLat, summary.MaxLon = decodePBFLatLng(bbox.Message())
+				default:
+					bbox.Skip()
+				}
+			}
+		default:
+			r.Skip()
+		}
+	}
+
+	return summary
+}
+
+func decodePBFStreetName(r *pbf.Reader) string {
+	name := ""
+	for r.Next() {
+		if r.Field() == pbfStreetNameValue {
+			name = r.String()
+		} else {
+			r.Skip()
+		}
+	}
+
+	return name
+}
+
+func decodePBFManeuver(r *pbf.Reader) *RouteOutputManeuver {
+	// proto3 omits zero values, json responses always have them
+	maneuver := &RouteOutputManeuver{
+		Type:            new(ManeuverType),
+		Time:            new(float64),
+		Length:          new(float64),
+		BeginShapeIndex: new(int),
+		EndShapeIndex:   new(int),
+	}
+	travelMode := uint64(0)
+	travelTypes := [4]uint64{} // vehicle, pedestrian, bicycle and transit types
+
+	str := func() *string {
+		s := r.String()
+		return &s
+	}
+
+	integer := func() *int {
+		i := int(r.Uint32())
+		return &i
+	}
+
+	boolean := func() *bool {
+		b := r.Bool()
+		return &b
+	}
+
+	for r.Next() {
+		switch r.Field() {
+		case pbfManeuverType:
+			maneuverType := ManeuverType(r.Uint32())
+			maneuver.Type = &maneuverType
+		case pbfManeuverTextInstruction:
+			maneuver.Instruction = str()
+		case pbfManeuverStreetName:
+			maneuver.StreetNames = append(maneuver.StreetNames, decodePBFStreetName(r.Message()))
+		case pbfManeuverBeginStreetName:
+			maneuver.BeginStreetNames = append(maneuver.BeginStreetNames, decodePBFStreetName(r.Message()))
+		case pbfManeuverLength:
+			length := float64(r.Float())
+			maneuver.Length = &length
+		case pbfManeuverTime:
+			t := r.Double()
+			maneuver.Time = &t
+		case pbfManeuverBeginShapeIndex:
+			maneuver.BeginShapeIndex = integer()
+		case pbfManeuverEndShapeIndex:
+			maneuver.EndShapeIndex = integer()
+		case pbfManeuverPortionsToll:
+			maneuver.Toll = boolean()
+		case pbfManeuverPortionsUnpaved:
+			maneuver.Rough = boolean()
+		case pbfManeuverPortionsFerry:
+			maneuver.Ferry = boolean()
+		case pbfManeuverVerbalTransitionAlertInstruction:
+			maneuver.VerbalTransitionAlertInstruction = str()
+		case pbfManeuverVerbalSuccinctTransitionInstruction:
+			maneuver.VerbalSuccinctTransitionInstruction = str()
+		case pbfManeuverVerbalPreTransitionInstruction:
+			maneuver.VerbalPreTransitionInstruction = str()
+		case pbfManeuverVerbalPostTransitionInstruction:
+			maneuver.VerbalPostTransitionInstruction = str()
+		case pbfManeuverRoundaboutExitCount:
+			maneuver.RoundaboutExitCount = integer()
+		case pbfManeuverDepartInstruction:
+			maneuver.DepartInstruction = str()
+		case pbfManeuverVerbalDepartInstruction:
+			maneuver.VerbalDepartInstruction = str()
+		case pbfManeuverArriveInstruction:
+			maneuver.ArriveInstruction = str()
+		case pbfManeuverVerbalArriveInstruction:
+			maneuver.VerbalArriveInstruction = str()
+		case pbfManeuverVerbalMultiCue:
+			maneuver.VerbalMultiCue = boolean()
+		case pbfManeuverTravelMode:
+			travelMode = r.Uint64()
+		case pbfManeuverVehicleType, pbfManeuverPedestrianType, pbfManeuverBicycleType, pbfManeuverTransitType:
+			travelTypes[r.Field()-pbfManeuverVehicleType] = r.Uint64()
+		default:
+			r.Skip()
+		}
+	}
+
+	// Travel type depends on the travel mode, as in json responses
+	if travelMode < uint64(len(pbfTravelModes)) {
+		maneuver.TravelMode = &pbfTravelModes[travelMode]
+
+		if travelType := travelTypes[travelMode]; travelType < uint64(len(pbfTravelTypes[travelMode])) {
+			maneuver.TravelType = &pbfTravelTypes[travelMode][travelType]
+		}
+	}
+
+	return maneuver
+}
+
+// unmarshalPBF decodes the matrix of a valhalla pbf response.
+// Distances are converted from meters to the response units.
+// Sources and targets locations are not decoded.
+func (output *MatrixOutput) unmarshalPBF(data []byte) error {
+	var (
+		options         = &pbfOptions{}
+		distances       []uint32
+		times           []float32
+		fromIndices     []uint32
+		toIndices       []uint32
+		dateTimes       []string
+		timeZoneOffsets []string
+		timeZoneNames   []string
+		hasMatrix       bool
+	)
+
+	r := pbf.NewReader(data)
+	for r.Next() {
+		switch r.Field() {
+		case pbfAPIOptions:
+			options = decodePBFOptions(r.Message())
+		case pbfAPIMatrix:
+			hasMatrix = true
+			matrix := r.Message()
+			for matrix.Next() {
+				switch matrix.Field() {
+				case pbfMatrixDistances:
+					distances = matrix.PackedUint32(distances)
+				case pbfMatrixTimes:
+					times = matrix.PackedFloat(times)
+				case pbfMatrixFromIndices:
+					fromIndices = matrix.PackedUint32(fromIndices)
+				case pbfMatrixToIndices:
+					toIndices = matrix.PackedUint32(toIndices)
+				case pbfMatrixDateTimes:
+					dateTimes = append(dateTimes, matrix.String())
+				case pbfMatrixTimeZoneOffsets:
+					timeZoneOffsets = append(timeZoneOffsets, matrix.String())
+				case pbfMatrixTimeZoneNames:
+					timeZoneNames = append(timeZoneNames, matrix.String())
+				default:
+					matrix.Skip()
+				}
+			}
+		default:
+			r.Skip()
+		}
+	}
+
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	if !hasMatrix {
+		return errPBFNoMatrix
+	}
+
+	if len(fromIndices) != len(times) || len(toIndices) != len(times) || len(distances) != len(times) {
+		return pbf.ErrMalformed
+	}
+
+	output.ID = options.id
+	output.Units = pbfEnum(pbfUnits, options.units)
+
+	distanceFactor := 1.0 / 1000
+	if options.units == 1 {
+		distanceFactor = 1 / metersPerMile
+	}
+
+	optional := func(values []string, i int) *string {
+		if i >= len(values) || values[i] == "" {
+			return nil
+		}
+
+		return &values[i]
+	}
+
+	for i := range times {
+		// Each source has a cell per target: a source index can't exceed the cells count
+		fromIndex, toIndex := int(fromIndices[i]), int(toIndices[i])
+		if fromIndex >= len(times) || toIndex >= len(times) {
+			return pbf.ErrMalformed
+		}
+
+		for len(output.SourcesToTargets) <= fromIndex {
+			output.SourcesToTargets = append(output.SourcesToTargets, nil)
+		}
+
+		cell := &MatrixOutputCell{
+			FromIndex:      &fromIndex,
+			ToIndex:        &toIndex,
+			DateTime:       optional(dateTimes, i),
+			TimeZoneOffset: optional(timeZoneOffsets, i),
+			TimeZoneName:   optional(timeZoneNames, i),
+		}
+
+		if times[i] < pbfMatrixUnreachable {
+			t := float64(times[i])
+			distance := float64(distances[i]) * distanceFactor
+			cell.Time = &t
+			cell.Distance = &distance
+		}
+
+		output.SourcesToTargets[fromIndex] = append(output.SourcesToTargets[fromIndex], cell)
+	}
+
+	return nil
+}
@@ -0,0 +1,396 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/angelodlfrtr/valhalla-http-client-go/internal/pbftest"
+	"github.com/angelodlfrtr/valhalla-http-client-go/pbf"
+	"github.com/goccy/go-json"
+	"github.com/gotidy/ptr"
+	"github.com/valyala/fasthttp"
+)
+
+// encodeTestLatLng encodes an api.proto LatLng
+func encodeTestLatLng(lat, lon float64) []byte {
+	b := pbftest.AppendDouble(nil, pbfLatLngLat, lat)
+	return pbftest.AppendDouble(b, pbfLatLngLng, lon)
+}
+
+// encodeTestRoutePBF encodes output as a valhalla pbf route response.
+// Only the fields decoded by RouteOutput.unmarshalPBF are encoded.
+func encodeTestRoutePBF(output *RouteOutput, locations [][]*RouteLocation) []byte {
+	data := []byte{}
+	if output.ID != nil {
+		data = pbftest.AppendBytes(data, pbfAPIOptions, pbftest.AppendString(nil, pbfOptionsID, *output.ID))
+	}
+
+	route := []byte{}
+	for legIndex, leg := range output.Trip.Legs {
+		l := []byte{}
+		for _, location := range locations[legIndex] {
+			loc := pbftest.AppendBytes(nil, pbfLocationLatLng, encodeTestLatLng(*location.Lat, *location.Lon))
+			loc = pbftest.AppendString(loc, pbfLocationName, *location.Name)
+			l = pbftest.AppendBytes(l, pbfLegLocation, loc)
+		}
+
+		summary := pbftest.AppendFloat(nil, pbfSummaryLength, float32(*leg.Summary.Length))
+		summary = pbftest.AppendDouble(summary, pbfSummaryTime, *leg.Summary.Time)
+		bbox := pbftest.AppendBytes(nil, pbfBBoxMin, encodeTestLatLng(*leg.Summary.MinLat, *leg.Summary.MinLon))
+		bbox = pbftest.AppendBytes(bbox, pbfBBoxMax, encodeTestLatLng(*leg.Summary.MaxLat, *leg.Summary.MaxLon))
+		summary = pbftest.AppendBytes(summary, pbfSummaryBBox, bbox)
+		l = pbftest.AppendBytes(l, pbfLegSummary, summary)
+
+		for _, maneuver := range leg.Maneuvers {
+			m := pbftest.AppendUint64(nil, pbfManeuverType, uint64(*maneuver.Type))
+			m = pbftest.AppendString(m, pbfManeuverTextInstruction, *maneuver.Instruction)
+			for _, name := range maneuver.StreetNames {
+				m = pbftest.AppendBytes(m, pbfManeuverStreetName, pbftest.AppendString(nil, pbfStreetNameValue, name))
+			}
+			m = pbftest.AppendFloat(m, pbfManeuverLength, float32(*maneuver.Length))
+			m = pbftest.AppendDouble(m, pbfManeuverTime, *maneuver.Time)
+			m = pbftest.AppendUint64(m, pbfManeuverBeginShapeIndex, uint64(*maneuver.BeginShapeIndex))
+			m = pbftest.AppendUint64(m, pbfManeuverEndShapeIndex, uint64(*maneuver.EndShapeIndex))
+			m = pbftest.AppendBool(m, pbfManeuverPortionsToll, *maneuver.Toll)
+			m = pbftest.AppendUint64(m, pbfManeuverTravelMode, 1)
+			m = pbftest.AppendUint64(m, pbfManeuverPedestrianType, 1)
+			l = pbftest.AppendBytes(l, pbfLegManeuver, m)
+		}
+
+		l = pbftest.AppendString(l, pbfLegShape, *leg.Shape)
+		route = pbftest.AppendBytes(route, pbfRouteLegs, l)
+	}
+
+	directions := pbftest.AppendBytes(nil, pbfDirectionsRoutes, route)
+	// Alternate route, ignored
+	directions = pbftest.AppendBytes(directions, pbfDirectionsRoutes, nil)
+
+	return pbftest.AppendBytes(data, pbfAPIDirections, directions)
+}
+
+// getTestLargeRouteOutput returns a route with legs legs of maneuvers maneuvers,
+// and the locations of each leg
+func getTestLargeRouteOutput(legs, maneuvers int) (*RouteOutput, [][]*RouteLocation) {
+	output := &RouteOutput{ID: ptr.String("large"), Trip: &RouteOutputTrip{Summary: &RouteOutputTripSummary{}}}
+	locations := [][]*RouteLocation{}
+	pedestrian, wheelchair := TravelModePedestrian, TravelTypeWheelchair
+
+	for i := 0; i < legs; i++ {
+		from := &RouteLocation{
+			Lat: ptr.Float64(48 + float64(i)/100), Lon: ptr.Float64(-4),
+			Type: ptr.String(RouteInputLocationTypeBreak), Name: ptr.String(fmt.Sprintf("loc %d", i)),
+		}
+		to := &RouteLocation{
+			Lat: ptr.Float64(48 + float64(i+1)/100), Lon: ptr.Float64(-4),
+			Type: ptr.String(RouteInputLocationTypeBreak), Name: ptr.String(fmt.Sprintf("loc %d", i+1)),
+		}
+		locations = append(locations, []*RouteLocation{from, to})
+
+		if i == 0 {
+			output.Trip.Locations = append(output.Trip.Locations, from)
+		}
+		output.Trip.Locations = append(output.Trip.Locations, to)
+
+		leg := &RouteOutputLeg{
+			Shape: ptr.String("_p~iF~ps|U_ulLnnqC_mqNvxq`@"),
+			Summary: &RouteOutputTripSummary{
+				Time: ptr.Float64(120.5), Length: ptr.Float64(1.25), HasTimeRestrictions: ptr.Bool(false),
+				MinLat: from.Lat, MinLon: ptr.Float64(-4), MaxLat: to.Lat, MaxLon: ptr.Float64(-4),
+			},
+		}
+
+		for j := 0; j < maneuvers; j++ {
+			leg.Maneuvers = append(leg.Maneuvers, &RouteOutputManeuver{
+				Type:            maneuverTypePtr(ManeuverTypeRight),
+				Instruction:     ptr.String("Turn right onto Rue de Siam."),
+				StreetNames:     []string{"Rue de Siam"},
+				Time:            ptr.Float64(12.5),
+				Length:          ptr.Float64(0.5),
+				BeginShapeIndex: ptr.Int(j),
+				EndShapeIndex:   ptr.Int(j + 1),
+				Toll:            ptr.Bool(false),
+				TravelMode:      &pedestrian,
+				TravelType:      &wheelchair,
+			})
+		}
+
+		output.Trip.Legs = append(output.Trip.Legs, leg)
+		mergeTripSummary(output.Trip.Summary, leg.Summary)
+	}
+
+	return output, locations
+}
+
+func TestRouteOutputUnmarshalPBF(t *testing.T) {
+	expected, locations := getTestLargeRouteOutput(3, 4)
+
+	output := &RouteOutput{}
+	if err := output.unmarshalPBF(encodeTestRoutePBF(expected, locations)); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedJSON, _ := json.Marshal(expected)
+	outputJSON, _ := json.Marshal(output)
+
+	if !bytes.Equal(expectedJSON, outputJSON) {
+		t.Fatalf("unexpected output\nexpected: %s\ngot:      %s", expectedJSON, outputJSON)
+	}
+
+	if *output.Trip.Summary.Time != 3*120.5 || len(output.Trip.Locations) != 4 {
+		t.Fatalf("unexpected trip summary %+v", output.Trip.Summary)
+	}
+
+	data := encodeTestRoutePBF(expected, locations)
+	if err := (&RouteOutput{}).unmarshalPBF(data[:len(data)-10]); err != pbf.ErrMalformed {
+		t.Fatalf("expected malformed error, got %v", err)
+	}
+}
+
+// The testdata pbf responses are encoded from the field numbers of the valhalla
+// api.proto, options.proto, directions.proto, matrix.proto and common.proto messages,
+// independently of the pbf field constants, with fields not decoded by the client.
+// As valhalla, zero values are omitted and unreachable matrix cells have the max cost.
+
+func TestRouteOutputUnmarshalPBFFixture(t *testing.T) {
+	data, err := os.ReadFile("testdata/route.pbf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := &RouteOutput{}
+	if err := output.unmarshalPBF(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if output.ID == nil || *output.ID != "brest" || output.Trip == nil || len(output.Trip.Legs) != 1 {
+		t.Fatalf("unexpected output %+v", output)
+	}
+
+	summary := output.Trip.Summary
+	if *summary.Time != 833.75 || float32(*summary.Length) != 19.952 || *summary.MinLat != 48.390394 ||
+		*summary.MaxLon != -4.25252 || *summary.HasTimeRestrictions {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+
+	locations := output.Trip.Locations
+	if len(locations) != 2 || *locations[0].Name != "Brest" || *locations[0].Street != "Rue de Siam" ||
+		*locations[0].Type != RouteInputLocationTypeBreak || *locations[1].Type != RouteInputLocationTypeThrough ||
+		*locations[1].Heading != 90 || *locations[1].SideOfStreet != "right" || *locations[1].DateTime != "2022-08-09T08:30" {
+		t.Fatalf("unexpected locations %+v %+v", locations[0], locations[1])
+	}
+
+	leg := output.Trip.Legs[0]
+	if *leg.Shape != "snoh{AvzxpGkl@kmBcky@{_~Ekp|@_udF" || len(leg.Maneuvers) != 3 {
+		t.Fatalf("unexpected leg %+v", leg)
+	}
+
+	start, right, destination := leg.Maneuvers[0], leg.Maneuvers[1], leg.Maneuvers[2]
+	if *start.Type != ManeuverTypeStart || *start.BeginShapeIndex != 0 || *start.EndShapeIndex != 1 ||
+		*start.VerbalPostTransitionInstruction != "Continue for 200 meters." || *start.VerbalSuccinctTransitionInstruction != "Drive east." ||
+		*start.TravelMode != TravelModeDrive || *start.TravelType != TravelTypeCar {
+		t.Fatalf("unexpected start maneuver %+v", start)
+	}
+
+	if *right.Type != ManeuverTypeRight || fmt.Sprint(right.StreetNames) != "[N12 Voie Express]" || *right.Time != 812.25 ||
+		float32(*right.Length) != 19.8 || *right.BeginShapeIndex != 1 || !*right.Toll {
+		t.Fatalf("unexpected right maneuver %+v", right)
+	}
+
+	if *destination.Type != ManeuverTypeDestinationRight || *destination.ArriveInstruction != "Arrive at your destination." ||
+		*destination.VerbalArriveInstruction != "Your destination is on the right." {
+		t.Fatalf("unexpected destination maneuver %+v", destination)
+	}
+}
+
+func TestRoutePBF(t *testing.T) {
+	expected, locations := getTestLargeRouteOutput(1, 2)
+
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		if !bytes.Contains(ctx.PostBody(), []byte(`"format":"pbf"`)) {
+			ctx.Error("expected pbf format", fasthttp.StatusBadRequest)
+			return
+		}
+
+		ctx.SetContentType(pbfContentType)
+		ctx.SetBody(encodeTestRoutePBF(expected, locations))
+	})
+
+	output, err := clt.Route(&RouteInput{Format: ptr.String(FormatPBF)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(output.Trip.Legs) != 1 || len(output.Trip.Legs[0].Maneuvers) != 2 || *output.ID != "large" {
+		t.Fatalf("unexpected output %+v", output)
+	}
+}
+
+// encodeTestMatrixPBF encodes a valhalla pbf matrix response of sources x targets cells,
+// the last cell is unreachable
+func encodeTestMatrixPBF(sources, targets int, units uint64) []byte {
+	var distances, fromIndices, toIndices []uint32
+	var times []float32
+
+	for i := 0; i < sources; i++ {
+		for j := 0; j < targets; j++ {
+			distances = append(distances, uint32(1000*(i+j)))
+			times = append(times, float32(60*(i+j)))
+			fromIndices = append(fromIndices, uint32(i))
+			toIndices = append(toIndices, uint32(j))
+		}
+	}
+
+	times[len(times)-1] = pbfMatrixUnreachable
+
+	matrix := pbftest.AppendPackedUint32(nil, pbfMatrixDistances, distances)
+	matrix = pbftest.AppendPackedFloat(matrix, pbfMatrixTimes, times)
+	matrix = pbftest.AppendPackedUint32(matrix, pbfMatrixFromIndices, fromIndices)
+	matrix = pbftest.AppendPackedUint32(matrix, pbfMatrixToIndices, toIndices)
+
+	data := pbftest.AppendBytes(nil, pbfAPIOptions, pbftest.AppendUint64(nil, pbfOptionsUnits, units))
+	return pbftest.AppendBytes(data, pbfAPIMatrix, matrix)
+}
+
+func TestMatrixPBF(t *testing.T) {
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		ctx.SetContentType(pbfContentType)
+		ctx.SetBody(encodeTestMatrixPBF(2, 3, 1))
+	})
+
+	output, err := clt.Matrix(&MatrixInput{Format: ptr.String(FormatPBF)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(output.SourcesToTargets) != 2 || len(output.SourcesToTargets[1]) != 3 || *output.Units != "miles" {
+		t.Fatalf("unexpected output %+v", output)
+	}
+
+	cell := output.Cell(1, 1)
+	if *cell.Time != 120 || *cell.Distance != 2000/metersPerMile {
+		t.Fatalf("unexpected cell %+v", cell)
+	}
+
+	if output.Cell(1, 2).Reachable() {
+		t.Fatal("expected last cell to be unreachable")
+	}
+
+	// Sources and targets are not decoded from pbf responses
+	if output.Sources != nil || output.Targets != nil {
+		t.Fatalf("expected no sources and targets, got %v and %v", output.Sources, output.Targets)
+	}
+}
+
+func TestMatrixOutputUnmarshalPBFFixture(t *testing.T) {
+	data, err := os.ReadFile("testdata/matrix.pbf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := &MatrixOutput{}
+	if err := output.unmarshalPBF(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if *output.ID != "commute" || *output.Units != "miles" || len(output.SourcesToTargets) != 2 {
+		t.Fatalf("unexpected output %+v", output)
+	}
+
+	cell := output.Cell(0, 1)
+	if *cell.Time != 900.5 || math.Abs(*cell.Distance-16093/metersPerMile) > 1e-9 || *cell.DateTime != "2022-08-09T08:15" ||
+		*cell.TimeZoneOffset != "+02:00" || *cell.TimeZoneName != "Europe/Paris" {
+		t.Fatalf("unexpected cell %+v", cell)
+	}
+
+	if cell := output.Cell(1, 1); cell.Reachable() || cell.DateTime != nil || cell.TimeZoneName != nil {
+		t.Fatalf("expected unreachable cell without date time, got %+v", cell)
+	}
+}
+
+func TestMatrixOutputUnmarshalPBFInvalid(t *testing.T) {
+	options := pbftest.AppendBytes(nil, pbfAPIOptions, pbftest.AppendString(nil, pbfOptionsID, "empty"))
+	if err := (&MatrixOutput{}).unmarshalPBF(options); err != errPBFNoMatrix {
+		t.Fatalf("expected no matrix error, got %v", err)
+	}
+
+	matrix := pbftest.AppendPackedUint32(nil, pbfMatrixDistances, []uint32{1000})
+	matrix = pbftest.AppendPackedFloat(matrix, pbfMatrixTimes, []float32{60})
+	matrix = pbftest.AppendPackedUint32(matrix, pbfMatrixFromIndices, []uint32{math.MaxUint32 - 1})
+	matrix = pbftest.AppendPackedUint32(matrix, pbfMatrixToIndices, []uint32{0})
+
+	if err := (&MatrixOutput{}).unmarshalPBF(pbftest.AppendBytes(nil, pbfAPIMatrix, matrix)); err != pbf.ErrMalformed {
+		t.Fatalf("expected malformed error, got %v", err)
+	}
+}
+
+func BenchmarkRouteOutputDecode(b *testing.B) {
+	output, locations := getTestLargeRouteOutput(20, 200)
+
+	jsonData, err := json.Marshal(output)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	pbfData := encodeTestRoutePBF(output, locations)
+
+	b.Run("json", func(b *testing.B) {
+		b.SetBytes(int64(len(jsonData)))
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if err := json.Unmarshal(jsonData, &RouteOutput{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("pbf", func(b *testing.B) {
+		b.SetBytes(int64(len(pbfData)))
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if err := (&RouteOutput{}).unmarshalPBF(pbfData); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkMatrixOutputDecode(b *testing.B) {
+	pbfData := encodeTestMatrixPBF(100, 100, 0)
+
+	output := &MatrixOutput{}
+	if err := output.unmarshalPBF(pbfData); err != nil {
+		b.Fatal(err)
+	}
+
+	jsonData, err := json.Marshal(output)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("json", func(b *testing.B) {
+		b.SetBytes(int64(len(jsonData)))
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if err := json.Unmarshal(jsonData, &MatrixOutput{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("pbf", func(b *testing.B) {
+		b.SetBytes(int64(len(pbfData)))
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if err := (&MatrixOutput{}).unmarshalPBF(pbfData); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// DefaultAPIKeyParam name of the query parameter the api key is sent in
+	DefaultAPIKeyParam = "api_key"
+
+	// tokenExpiryMargin a token is refreshed this long before its expiry
+	tokenExpiryMargin = 10 * time.Second
+)
+
+// TokenSource provides bearer tokens, it is called before each request
+// and is responsible for refreshing the token when needed
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc is a function implementing TokenSource
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls fn
+func (fn TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return fn(ctx)
+}
+
+// TokenFetchFn fetches a new token and returns its expiry time.
+// A zero expiry means the token never expires.
+type TokenFetchFn func(ctx context.Context) (token string, expiry time.Time, err error)
+
+// reuseTokenSource caches the token fetched by fetch until it expires
+type reuseTokenSource struct {
+	fetch TokenFetchFn
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// ReuseTokenSource returns a TokenSource caching the token fetched by fetch,
+// a new token is fetched shortly before the current one expires
+func ReuseTokenSource(fetch TokenFetchFn) TokenSource {
+	return &reuseTokenSource{fetch: fetch}
+}
+
+// Token returns the cached token, fetching a new one if expired
+func (src *reuseTokenSource) Token(ctx context.Context) (string, error) {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+
+	if src.token != "" && (src.expiry.IsZero() || time.Now().Add(tokenExpiryMargin).Before(src.expiry)) {
+		return src.token, nil
+	}
+
+	token, expiry, err := src.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	src.token = token
+	src.expiry = expiry
+
+	return token, nil
+}
+
+// AuthConfig configures the authentication of requests.
+// API key and bearer token can be used together.
+type AuthConfig struct {
+	// APIKey (optional) api key sent with each request, as required by hosted valhalla providers.
+	APIKey string `json:"api_key" yaml:"api_key"`
+
+	// APIKeyParam name of the query parameter the api key is sent in.
+	// Default DefaultAPIKeyParam. Ignored if APIKeyHeader is set.
+	APIKeyParam string `json:"api_key_param" yaml:"api_key_param"`
+
+	// APIKeyHeader (optional) name of the header the api key is sent in,
+	// instead of a query parameter.
+	APIKeyHeader string `json:"api_key_header" yaml:"api_key_header"`
+
+	// BearerToken (optional) static token sent in the Authorization header.
+	BearerToken string `json:"bearer_token" yaml:"bearer_token"`
+
+	// TokenSource (optional) source of bearer tokens sent in the Authorization header,
+	// takes precedence over BearerToken.
+	TokenSource TokenSource `json:"-" yaml:"-"`
+}
+
+// apply sets auth query parameters and headers on req
+func (auth *AuthConfig) apply(ctx context.Context, req *fasthttp.Request) error {
+	if auth.APIKey != "" {
+		if auth.APIKeyHeader != "" {
+			req.Header.Set(auth.APIKeyHeader, auth.APIKey)
+		} else {
+			param := auth.APIKeyParam
+			if param == "" {
+				param = DefaultAPIKeyParam
+			}
+
+			req.URI().QueryArgs().Set(param, auth.APIKey)
+		}
+	}
+
+	token := auth.BearerToken
+	if auth.TokenSource != nil {
+		var err error
+		if token, err = auth.TokenSource.Token(ctx); err != nil {
+			return fmt.Errorf("unable to get auth token: %w", err)
+		}
+	}
+
+	if token != "" {
+		req.Header.Set(fasthttp.HeaderAuthorization, "Bearer "+token)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientHeadersAndAuth(t *testing.T) {
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		if string(ctx.Request.Header.Peek("X-Team")) != "dispatch" {
+			ctx.Error("missing custom header", fasthttp.StatusBadRequest)
+			return
+		}
+
+		if string(ctx.QueryArgs().Peek(DefaultAPIKeyParam)) != "secret" {
+			ctx.Error("missing api key", fasthttp.StatusUnauthorized)
+			return
+		}
+
+		if string(ctx.Request.Header.Peek(fasthttp.HeaderAuthorization)) != "Bearer token-1" {
+			ctx.Error("missing bearer token", fasthttp.StatusUnauthorized)
+			return
+		}
+
+		ctx.SetBodyString(`{}`)
+	})
+
+	fetches := 0
+	clt.config.CustomHeaders = map[string]string{"X-Team": "dispatch"}
+	clt.config.Auth = &AuthConfig{
+		APIKey: "secret",
+		TokenSource: ReuseTokenSource(func(ctx context.Context) (string, time.Time, error) {
+			fetches++
+			return "token-1", time.Now().Add(time.Hour), nil
+		}),
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := clt.Elevation(&ElevationInput{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if fetches != 1 {
+		t.Fatalf("expected token to be fetched once, got %d", fetches)
+	}
+}
+
+func TestAuthAPIKeyHeader(t *testing.T) {
+	auth := &AuthConfig{APIKey: "secret", APIKeyHeader: "X-Api-Key", BearerToken: "static"}
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+
+	req.SetRequestURI("http://valhalla.local/route")
+
+	if err := auth.apply(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(req.Header.Peek("X-Api-Key")) != "secret" || req.URI().QueryArgs().Has(DefaultAPIKeyParam) {
+		t.Fatalf("expected api key in header only, got %s", req.String())
+	}
+
+	if string(req.Header.Peek(fasthttp.HeaderAuthorization)) != "Bearer static" {
+		t.Fatalf("expected static bearer token, got %s", req.String())
+	}
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+// Balancer picks the endpoint a request is sent to
+type Balancer interface {
+	// Pick returns one of the given endpoints, endpoints is never empty
+	Pick(endpoints []*Endpoint) *Endpoint
+}
+
+// RoundRobinBalancer picks endpoints in turn
+type RoundRobinBalancer struct {
+	next uint64
+}
+
+// Pick returns the next endpoint
+func (balancer *RoundRobinBalancer) Pick(endpoints []*Endpoint) *Endpoint {
+	next := atomic.AddUint64(&balancer.next, 1) - 1
+	return endpoints[next%uint64(len(endpoints))]
+}
+
+// LeastInFlightBalancer picks the endpoint with the fewest requests in flight
+type LeastInFlightBalancer struct{}
+
+// Pick returns the endpoint with the fewest requests in flight
+func (balancer *LeastInFlightBalancer) Pick(endpoints []*Endpoint) *Endpoint {
+	picked := endpoints[0]
+	for _, endpoint := range endpoints[1:] {
+		if endpoint.InFlight() < picked.InFlight() {
+			picked = endpoint
+		}
+	}
+
+	return picked
+}
+
+// WeightedBalancer picks endpoints randomly, proportionally to their weight
+type WeightedBalancer struct{}
+
+// Pick returns a random endpoint, proportionally to its weight
+func (balancer *WeightedBalancer) Pick(endpoints []*Endpoint) *Endpoint {
+	total := 0
+	for _, endpoint := range endpoints {
+		total += endpoint.Weight()
+	}
+
+	n := rand.Intn(total) //nolint:gosec
+	for _, endpoint := range endpoints {
+		n -= endpoint.Weight()
+		if n < 0 {
+			return endpoint
+		}
+	}
+
+	return endpoints[len(endpoints)-1]
+}
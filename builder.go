@@ -0,0 +1,331 @@
+package client
+
+import (
+	"time"
+)
+
+// LocationOption configures a location built by Loc
+type LocationOption func(location *RouteLocation)
+
+// Loc returns a location at lat, lon configured by opts
+func Loc(lat, lon float64, opts ...LocationOption) *RouteLocation {
+	location := &RouteLocation{Lat: &lat, Lon: &lon}
+	for _, opt := range opts {
+		opt(location)
+	}
+
+	return location
+}
+
+// WithType sets the type of the location (ie: RouteInputLocationTypeThrough)
+func WithType(locationType string) LocationOption {
+	return func(location *RouteLocation) {
+		location.Type = &locationType
+	}
+}
+
+// WithName sets the name of the location
+func WithName(name string) LocationOption {
+	return func(location *RouteLocation) {
+		location.Name = &name
+	}
+}
+
+// WithStreet sets the street name of the location, used to improve the snapping
+func WithStreet(street string) LocationOption {
+	return func(location *RouteLocation) {
+		location.Street = &street
+	}
+}
+
+// WithHeading sets the preferred heading of the location, in degrees from north,
+// and the tolerance of the heading
+func WithHeading(heading, tolerance float32) LocationOption {
+	return func(location *RouteLocation) {
+		location.Heading = &heading
+		location.HeadingTolerance = &tolerance
+	}
+}
+
+// WithRadius sets the radius in meters around the location in which edges are considered
+func WithRadius(radius int) LocationOption {
+	return func(location *RouteLocation) {
+		location.Radius = radius
+	}
+}
+
+// WithPreferredSide sets the side of street to visit (ie: RouteInputLocationPreferredSideSame)
+func WithPreferredSide(side string) LocationOption {
+	return func(location *RouteLocation) {
+		location.PreferredSide = &side
+	}
+}
+
+// WithSearchFilter sets the filter of the edges candidates to the location
+func WithSearchFilter(filter *RouteInputLocationSearchFilter) LocationOption {
+	return func(location *RouteLocation) {
+		location.SearchFilter = filter
+	}
+}
+
+// RouteBuilder builds a RouteInput.
+//
+//	input := NewRoute().
+//		From(48.390394, -4.486076).
+//		Via(48.40912, -4.39826).
+//		To(48.45252, -4.25252).
+//		Costing(CostingModelAuto).
+//		DepartAt(time.Now()).
+//		Build()
+type RouteBuilder struct {
+	input *RouteInput
+	from  *RouteLocation
+	stops []*RouteLocation
+	to    *RouteLocation
+}
+
+// NewRoute returns a builder of a RouteInput
+func NewRoute() *RouteBuilder {
+	return &RouteBuilder{input: &RouteInput{}}
+}
+
+// From sets the origin of the route, a break location unless set by opts
+func (b *RouteBuilder) From(lat, lon float64, opts ...LocationOption) *RouteBuilder {
+	b.from = Loc(lat, lon, opts...)
+	return b
+}
+
+// Via adds an intermediate via location: the route passes by the location
+// without stopping, u-turns being allowed
+func (b *RouteBuilder) Via(lat, lon float64, opts ...LocationOption) *RouteBuilder {
+	opts = append([]LocationOption{WithType(RouteInputLocationTypeVia)}, opts...)
+	return b.Stop(Loc(lat, lon, opts...))
+}
+
+// Through adds an intermediate through location: the route passes by the location
+// without stopping nor u-turn
+func (b *RouteBuilder) Through(lat, lon float64, opts ...LocationOption) *RouteBuilder {
+	opts = append([]LocationOption{WithType(RouteInputLocationTypeThrough)}, opts...)
+	return b.Stop(Loc(lat, lon, opts...))
+}
+
+// Stop adds an intermediate location, in order
+func (b *RouteBuilder) Stop(location *RouteLocation) *RouteBuilder {
+	b.stops = append(b.stops, location)
+	return b
+}
+
+// To sets the destination of the route, a break location unless set by opts
+func (b *RouteBuilder) To(lat, lon float64, opts ...LocationOption) *RouteBuilder {
+	b.to = Loc(lat, lon, opts...)
+	return b
+}
+
+// Costing sets the costing model (ie: CostingModelAuto)
+func (b *RouteBuilder) Costing(costing string) *RouteBuilder {
+	b.input.Costing = &costing
+	return b
+}
+
+// CostingOptions sets the options of the costing models
+func (b *RouteBuilder) CostingOptions(options *CostingModelOptions) *RouteBuilder {
+	b.input.CostingOptions = options
+	return b
+}
+
+// Units sets the distance units of the output: km or miles
+func (b *RouteBuilder) Units(units string) *RouteBuilder {
+	b.input.Units = &units
+	return b
+}
+
+// Language sets the language of the narration instructions (ie: fr-FR)
+func (b *RouteBuilder) Language(language string) *RouteBuilder {
+	b.input.Language = &language
+	return b
+}
+
+// DirectionsType sets the directions type (ie: DirectionsTypeManeuvers)
+func (b *RouteBuilder) DirectionsType(directionsType string) *RouteBuilder {
+	b.input.DirectionsType = &directionsType
+	return b
+}
+
+// Alternates sets the number of alternate routes to compute
+func (b *RouteBuilder) Alternates(alternates int) *RouteBuilder {
+	b.input.Alternates = &alternates
+	return b
+}
+
+// Avoid adds a polygon ring to avoid, as [lon, lat] coordinates
+func (b *RouteBuilder) Avoid(ring [][]float64) *RouteBuilder {
+	b.input.ExcludePolygons = append(b.input.ExcludePolygons, ring)
+	return b
+}
+
+// AvoidLocation adds a location whose closest roads are avoided
+func (b *RouteBuilder) AvoidLocation(lat, lon float64) *RouteBuilder {
+	b.input.ExcludeLocations = append(b.input.ExcludeLocations, Loc(lat, lon))
+	return b
+}
+
+// DepartAt sets the departure time, see DepartAt
+func (b *RouteBuilder) DepartAt(t time.Time) *RouteBuilder {
+	b.input.DateTime = DepartAt(t)
+	return b
+}
+
+// ArriveBy sets the arrival time, see ArriveBy
+func (b *RouteBuilder) ArriveBy(t time.Time) *RouteBuilder {
+	b.input.DateTime = ArriveBy(t)
+	return b
+}
+
+// DateTime sets the date time of the route
+func (b *RouteBuilder) DateTime(dateTime *DateTime) *RouteBuilder {
+	b.input.DateTime = dateTime
+	return b
+}
+
+// Format sets the response format (ie: FormatPBF)
+func (b *RouteBuilder) Format(format string) *RouteBuilder {
+	b.input.Format = &format
+	return b
+}
+
+// ID sets the id of the request, returned in the response
+func (b *RouteBuilder) ID(id string) *RouteBuilder {
+	b.input.ID = &id
+	return b
+}
+
+// Build returns the built input: origin, intermediate locations then destination.
+// The builder must not be used after Build.
+func (b *RouteBuilder) Build() *RouteInput {
+	locations := make([]*RouteLocation, 0, len(b.stops)+2)
+	if b.from != nil {
+		locations = append(locations, b.from)
+	}
+
+	locations = append(locations, b.stops...)
+
+	if b.to != nil {
+		locations = append(locations, b.to)
+	}
+
+	b.input.Locations = locations
+
+	return b.input
+}
+
+// IsochroneBuilder builds an IsochroneInput.
+//
+//	input := NewIsochrone().
+//		At(48.390394, -4.486076).
+//		Costing(CostingModelPedestrian).
+//		Minutes(10, 20).
+//		Polygons().
+//		Build()
+type IsochroneBuilder struct {
+	input *IsochroneInput
+}
+
+// NewIsochrone returns a builder of an IsochroneInput
+func NewIsochrone() *IsochroneBuilder {
+	return &IsochroneBuilder{input: &IsochroneInput{}}
+}
+
+// At adds a location of the isochrone
+func (b *IsochroneBuilder) At(lat, lon float64) *IsochroneBuilder {
+	b.input.Locations = append(b.input.Locations, &IsochroneInputLocation{Lat: &lat, Lon: &lon})
+	return b
+}
+
+// Costing sets the costing model (ie: CostingModelPedestrian)
+func (b *IsochroneBuilder) Costing(costing string) *IsochroneBuilder {
+	b.input.Costing = &costing
+	return b
+}
+
+// CostingOptions sets the options of the costing models
+func (b *IsochroneBuilder) CostingOptions(options *CostingModelOptions) *IsochroneBuilder {
+	b.input.CostingOptions = options
+	return b
+}
+
+// Minutes adds a time contour for each of minutes
+func (b *IsochroneBuilder) Minutes(minutes ...float64) *IsochroneBuilder {
+	for _, value := range minutes {
+		value := value
+		b.input.Contours = append(b.input.Contours, &IsochroneInputContour{Time: &value})
+	}
+
+	return b
+}
+
+// Kilometers adds a distance contour for each of kilometers
+func (b *IsochroneBuilder) Kilometers(kilometers ...float64) *IsochroneBuilder {
+	for _, value := range kilometers {
+		value := value
+		b.input.Contours = append(b.input.Contours, &IsochroneInputContour{Distance: &value})
+	}
+
+	return b
+}
+
+// Contour adds a contour
+func (b *IsochroneBuilder) Contour(contour *IsochroneInputContour) *IsochroneBuilder {
+	b.input.Contours = append(b.input.Contours, contour)
+	return b
+}
+
+// Polygons returns contours as polygons instead of lines
+func (b *IsochroneBuilder) Polygons() *IsochroneBuilder {
+	polygons := true
+	b.input.Polygons = &polygons
+
+	return b
+}
+
+// Denoise sets the factor from 0 to 1 removing the smallest contours
+func (b *IsochroneBuilder) Denoise(denoise float64) *IsochroneBuilder {
+	b.input.Denoise = &denoise
+	return b
+}
+
+// Generalize sets the tolerance in meters of the contours generalization
+func (b *IsochroneBuilder) Generalize(generalize float64) *IsochroneBuilder {
+	b.input.Generalize = &generalize
+	return b
+}
+
+// ShowLocations returns the input locations in the output
+func (b *IsochroneBuilder) ShowLocations() *IsochroneBuilder {
+	showLocations := true
+	b.input.ShowLocations = &showLocations
+
+	return b
+}
+
+// DepartAt sets the departure time, see DepartAt
+func (b *IsochroneBuilder) DepartAt(t time.Time) *IsochroneBuilder {
+	b.input.DateTime = DepartAt(t)
+	return b
+}
+
+// DateTime sets the date time of the isochrone
+func (b *IsochroneBuilder) DateTime(dateTime *DateTime) *IsochroneBuilder {
+	b.input.DateTime = dateTime
+	return b
+}
+
+// ID sets the id of the request, returned in the response
+func (b *IsochroneBuilder) ID(id string) *IsochroneBuilder {
+	b.input.ID = &id
+	return b
+}
+
+// Build returns the built input. The builder must not be used after Build.
+func (b *IsochroneBuilder) Build() *IsochroneInput {
+	return b.input
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gotidy/ptr"
+)
+
+func TestRouteBuilder(t *testing.T) {
+	departure := time.Date(2022, 8, 9, 8, 6, 0, 0, time.UTC)
+	polygon := [][]float64{{-4.4, 48.4}, {-4.3, 48.4}, {-4.3, 48.5}}
+
+	input := NewRoute().
+		To(48.45252, -4.25252, WithName("Guipavas")).
+		From(48.390394, -4.486076).
+		Via(48.40912, -4.39826, WithRadius(10)).
+		Costing(CostingModelAuto).
+		Avoid(polygon).
+		DepartAt(departure).
+		Build()
+
+	expected := &RouteInput{
+		Locations: []*RouteLocation{
+			{Lat: ptr.Float64(48.390394), Lon: ptr.Float64(-4.486076)},
+			{Lat: ptr.Float64(48.40912), Lon: ptr.Float64(-4.39826), Type: ptr.String(RouteInputLocationTypeVia), Radius: 10},
+			{Lat: ptr.Float64(48.45252), Lon: ptr.Float64(-4.25252), Name: ptr.String("Guipavas")},
+		},
+		Costing:         ptr.String(CostingModelAuto),
+		ExcludePolygons: [][][]float64{polygon},
+		DateTime:        &DateTime{Type: ptr.Int(1), Value: ptr.String("2022-08-09T08:06")},
+	}
+
+	inputJSON, _ := json.Marshal(input)
+	expectedJSON, _ := json.Marshal(expected)
+
+	if string(inputJSON) != string(expectedJSON) {
+		t.Fatalf("unexpected input\nexpected: %s\ngot:      %s", expectedJSON, inputJSON)
+	}
+
+	if err := input.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIsochroneBuilder(t *testing.T) {
+	input := NewIsochrone().
+		At(48.390394, -4.486076).
+		Costing(CostingModelPedestrian).
+		Minutes(10, 20).
+		Polygons().
+		Build()
+
+	if len(input.Contours) != 2 || *input.Contours[0].Time != 10 || *input.Contours[1].Time != 20 || !*input.Polygons {
+		t.Fatalf("unexpected input %+v", input)
+	}
+
+	if err := input.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIsochroneBuilderCopiesContours(t *testing.T) {
+	minutes, kilometers := []float64{10, 20}, []float64{5}
+	input := NewIsochrone().Minutes(minutes...).Kilometers(kilometers...).Build()
+
+	minutes[0], kilometers[0] = 30, 15
+
+	if *input.Contours[0].Time != 10 || *input.Contours[2].Distance != 5 {
+		t.Fatalf("expected contours not to change with the caller slices, got %+v", input.Contours)
+	}
+}
@@ -0,0 +1,247 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/goccy/go-json"
+)
+
+// Cache stores valhalla responses by request key.
+// Values must not be modified once stored or returned.
+type Cache interface {
+	// Get returns the value of key, false if key is not cached
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value for key
+	Set(ctx context.Context, key string, value []byte) error
+}
+
+// CacheConfig configures the caching of responses.
+// Responses are cached by action and canonicalised request json, successful responses only.
+type CacheConfig struct {
+	// Backend storing the responses, see NewMemoryCache and NewDiskCache.
+	Backend Cache `json:"-" yaml:"-"`
+
+	// Actions (optional) actions whose responses are cached, all actions if empty.
+	Actions []string `json:"actions" yaml:"actions"`
+
+	// CacheCurrentDateTime caches requests having a date_time of type 0 (current time),
+	// whose responses depend on the time of the request. Not cached by default.
+	CacheCurrentDateTime bool `json:"cache_current_date_time" yaml:"cache_current_date_time"`
+}
+
+// CacheResult how the cache was used for a request
+type CacheResult int
+
+// Cache results
+const (
+	// CacheResultNone caching is not configured
+	CacheResultNone CacheResult = iota
+
+	// CacheResultHit the response was read from the cache
+	CacheResultHit
+
+	// CacheResultMiss the response was not cached, it has been requested and stored
+	CacheResultMiss
+
+	// CacheResultBypass the cache was bypassed (see WithCacheBypass), the response has been requested and stored
+	CacheResultBypass
+
+	// CacheResultUncacheable the request can not be cached (ie: date_time of type 0 or action not cached)
+	CacheResultUncacheable
+)
+
+var cacheResultNames = []string{"none", "hit", "miss", "bypass", "uncacheable"}
+
+// String returns the name of the result
+func (result CacheResult) String() string {
+	if result < 0 || int(result) >= len(cacheResultNames) {
+		return "unknown"
+	}
+
+	return cacheResultNames[result]
+}
+
+// CacheStats counters of the cache usage of a client
+type CacheStats struct {
+	// Hits number of responses read from the cache
+	Hits uint64
+
+	// Misses number of cacheable responses not found in the cache
+	Misses uint64
+
+	// Errors number of errors returned by the cache backend, handled as misses
+	Errors uint64
+}
+
+type cacheCounters struct {
+	hits   uint64
+	misses uint64
+	errors uint64
+}
+
+type cacheBypassKey struct{}
+
+type cacheResultKey struct{}
+
+// WithCacheBypass returns a context whose requests do not read the cache.
+// Their responses are still stored, refreshing the cache.
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+// WithCacheResult returns a context whose requests report how the cache was used in result
+func WithCacheResult(ctx context.Context, result *CacheResult) context.Context {
+	return context.WithValue(ctx, cacheResultKey{}, result)
+}
+
+// reportCacheResult sets the cache result of the request of ctx, if requested
+func reportCacheResult(ctx context.Context, result CacheResult) {
+	if r, ok := ctx.Value(cacheResultKey{}).(*CacheResult); ok {
+		*r = result
+	}
+}
+
+// CacheStats returns the cache counters of the client
+func (client *Client) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadUint64(&client.cacheCounters.hits),
+		Misses: atomic.LoadUint64(&client.cacheCounters.misses),
+		Errors: atomic.LoadUint64(&client.cacheCounters.errors),
+	}
+}
+
+// cachedSend sends the request body of action through the cache
+func (client *Client) cachedSend(ctx context.Context, cfg *CacheConfig, action string, body []byte, output interface{}) error {
+	key, ok := cacheKey(cfg, action, body)
+	if !ok {
+		reportCacheResult(ctx, CacheResultUncacheable)
+		return client.send(ctx, action, body, output)
+	}
+
+	result := CacheResultBypass
+	if bypass, _ := ctx.Value(cacheBypassKey{}).(bool); !bypass {
+		value, found, err := cfg.Backend.Get(ctx, key)
+		if err != nil {
+			atomic.AddUint64(&client.cacheCounters.errors, 1)
+		}
+
+		// Cached values which can't be decoded are handled as misses, and overwritten
+		if raw, ok := decodeCacheValue(value); found && ok {
+			if err := decodeOutput(action, raw.pbf, raw.body, output); err == nil {
+				atomic.AddUint64(&client.cacheCounters.hits, 1)
+				reportCacheResult(ctx, CacheResultHit)
+
+				return nil
+			}
+
+			resetOutput(output)
+		}
+
+		atomic.AddUint64(&client.cacheCounters.misses, 1)
+		result = CacheResultMiss
+	}
+
+	reportCacheResult(ctx, result)
+
+	raw := &rawOutput{}
+	if err := client.send(ctx, action, body, raw); err != nil {
+		return err
+	}
+
+	// Responses which can't be decoded are not cached
+	if err := decodeOutput(action, raw.pbf, raw.body, output); err != nil {
+		return err
+	}
+
+	if err := cfg.Backend.Set(ctx, key, encodeCacheValue(raw)); err != nil {
+		atomic.AddUint64(&client.cacheCounters.errors, 1)
+	}
+
+	return nil
+}
+
+// resetOutput sets output, a pointer, to the zero value of its type
+func resetOutput(output interface{}) {
+	if v := reflect.ValueOf(output); v.Kind() == reflect.Ptr && !v.IsNil() {
+		v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	}
+}
+
+// cacheKey returns the cache key of the request body of action: the action and the
+// hash of the canonicalised body. Returns false if the request can not be cached.
+func cacheKey(cfg *CacheConfig, action string, body []byte) (string, bool) {
+	if len(cfg.Actions) > 0 && !containsString(cfg.Actions, action) {
+		return "", false
+	}
+
+	var input interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	if err := dec.Decode(&input); err != nil {
+		return "", false
+	}
+
+	if !cfg.CacheCurrentDateTime && hasCurrentDateTime(input) {
+		return "", false
+	}
+
+	// Maps are marshaled with sorted keys
+	canonical, err := json.Marshal(input)
+	if err != nil {
+		return "", false
+	}
+
+	sum := sha256.Sum256(canonical)
+
+	return action + ":" + hex.EncodeToString(sum[:]), true
+}
+
+// hasCurrentDateTime returns true if input has a date_time of type 0
+func hasCurrentDateTime(input interface{}) bool {
+	fields, ok := input.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	dateTime, ok := fields["date_time"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	dateTimeType, ok := dateTime["type"].(json.Number)
+
+	return ok && dateTimeType.String() == "0"
+}
+
+// Cached values are the response body prefixed by its format
+const (
+	cacheValueJSON byte = 'j'
+	cacheValuePBF  byte = 'p'
+)
+
+func encodeCacheValue(raw *rawOutput) []byte {
+	value := make([]byte, 0, len(raw.body)+1)
+	if raw.pbf {
+		value = append(value, cacheValuePBF)
+	} else {
+		value = append(value, cacheValueJSON)
+	}
+
+	return append(value, raw.body...)
+}
+
+func decodeCacheValue(value []byte) (*rawOutput, bool) {
+	if len(value) == 0 || (value[0] != cacheValueJSON && value[0] != cacheValuePBF) {
+		return nil, false
+	}
+
+	return &rawOutput{pbf: value[0] == cacheValuePBF, body: value[1:]}, true
+}
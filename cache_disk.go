@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	diskCacheFileExt    = ".cache"
+	diskCacheTempPrefix = ".tmp-"
+
+	// diskCacheTempMaxAge age after which temporary files, left by interrupted writes, are pruned
+	diskCacheTempMaxAge = 10 * time.Minute
+)
+
+// DiskCache a Cache storing each value in a file of a directory.
+// Entries expire TTL after their last write, based on the modification time of the files.
+type DiskCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewDiskCache returns a cache storing its values in dir, created if missing.
+// Entries never expire if ttl is 0.
+func NewDiskCache(dir string, ttl time.Duration) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error while creating cache directory: %w", err)
+	}
+
+	return &DiskCache{dir: dir, ttl: ttl}, nil
+}
+
+// Get implements Cache
+func (cache *DiskCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	path := cache.path(key)
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("error while reading cache entry: %w", err)
+	}
+
+	if cache.expired(info) {
+		return nil, false, nil
+	}
+
+	value, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("error while reading cache entry: %w", err)
+	}
+
+	return value, true, nil
+}
+
+// Set implements Cache. The value is written to a temporary file renamed once
+// complete, concurrent readers never see partial values.
+func (cache *DiskCache) Set(_ context.Context, key string, value []byte) error {
+	file, err := os.CreateTemp(cache.dir, diskCacheTempPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("error while writing cache entry: %w", err)
+	}
+
+	_, err = file.Write(value)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(file.Name(), cache.path(key))
+	}
+
+	if err != nil {
+		os.Remove(file.Name()) //nolint:errcheck
+		return fmt.Errorf("error while writing cache entry: %w", err)
+	}
+
+	return nil
+}
+
+// Prune removes the expired entries of the cache, and the temporary files left by interrupted writes
+func (cache *DiskCache) Prune() error {
+	entries, err := os.ReadDir(cache.dir)
+	if err != nil {
+		return fmt.Errorf("error while listing cache entries: %w", err)
+	}
+
+	for _, entry := range entries {
+		temp := strings.HasPrefix(entry.Name(), diskCacheTempPrefix)
+		if entry.IsDir() || (!temp && !strings.HasSuffix(entry.Name(), diskCacheFileExt)) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("error while reading cache entry: %w", err)
+		}
+
+		stale := cache.expired(info)
+		if temp {
+			stale = time.Since(info.ModTime()) >= diskCacheTempMaxAge
+		}
+
+		if !stale {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(cache.dir, entry.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("error while removing cache entry: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (cache *DiskCache) expired(info fs.FileInfo) bool {
+	return cache.ttl > 0 && time.Since(info.ModTime()) >= cache.ttl
+}
+
+// path returns the file path of key, characters other than [a-zA-Z0-9_-] being replaced
+func (cache *DiskCache) path(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+
+	return filepath.Join(cache.dir, name+diskCacheFileExt)
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryCacheConfig configures a MemoryCache
+type MemoryCacheConfig struct {
+	// MaxEntries (optional) maximum number of entries, least recently used entries are evicted first.
+	// Unbounded if 0.
+	MaxEntries int `json:"max_entries" yaml:"max_entries"`
+
+	// MaxBytes (optional) maximum total size of the values in bytes. Values larger than
+	// MaxBytes are not stored. Unbounded if 0.
+	MaxBytes int `json:"max_bytes" yaml:"max_bytes"`
+
+	// TTL (optional) duration an entry is kept. Entries never expire if 0.
+	TTL time.Duration `json:"ttl" yaml:"ttl"`
+}
+
+// MemoryCache an in-memory LRU Cache, safe for concurrent use
+type MemoryCache struct {
+	cfg MemoryCacheConfig
+
+	mu    sync.Mutex
+	lru   *list.List
+	items map[string]*list.Element
+	bytes int
+}
+
+type memoryCacheEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache returns an empty in-memory cache configured by cfg
+func NewMemoryCache(cfg MemoryCacheConfig) *MemoryCache {
+	return &MemoryCache{
+		cfg:   cfg,
+		lru:   list.New(),
+		items: map[string]*list.Element{},
+	}
+}
+
+// Get implements Cache
+func (cache *MemoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	element, ok := cache.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*memoryCacheEntry)
+	if !entry.expiresAt.IsZero() && !time.Now().Before(entry.expiresAt) {
+		cache.remove(element)
+		return nil, false, nil
+	}
+
+	cache.lru.MoveToFront(element)
+
+	return entry.value, true, nil
+}
+
+// Set implements Cache
+func (cache *MemoryCache) Set(_ context.Context, key string, value []byte) error {
+	entry := &memoryCacheEntry{key: key, value: value}
+	if cache.cfg.TTL > 0 {
+		entry.expiresAt = time.Now().Add(cache.cfg.TTL)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	// Previous value is removed even if value is too large to be stored
+	if element, ok := cache.items[key]; ok {
+		cache.remove(element)
+	}
+
+	if cache.cfg.MaxBytes > 0 && len(value) > cache.cfg.MaxBytes {
+		return nil
+	}
+
+	cache.items[key] = cache.lru.PushFront(entry)
+	cache.bytes += len(value)
+
+	for (cache.cfg.MaxEntries > 0 && cache.lru.Len() > cache.cfg.MaxEntries) ||
+		(cache.cfg.MaxBytes > 0 && cache.bytes > cache.cfg.MaxBytes) {
+		cache.remove(cache.lru.Back())
+	}
+
+	return nil
+}
+
+// Len returns the number of entries of the cache, including expired entries not yet evicted
+func (cache *MemoryCache) Len() int {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.lru.Len()
+}
+
+// remove removes element from the cache, the lock must be held
+func (cache *MemoryCache) remove(element *list.Element) {
+	entry := cache.lru.Remove(element).(*memoryCacheEntry)
+	delete(cache.items, entry.key)
+	cache.bytes -= len(entry.value)
+}
@@ -0,0 +1,265 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientCache(t *testing.T) {
+	calls := int64(0)
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{Endpoint: "http://valhalla.local", Cache: &CacheConfig{Backend: NewMemoryCache(MemoryCacheConfig{})}},
+		map[string]fasthttp.RequestHandler{"valhalla.local": func(ctx *fasthttp.RequestCtx) {
+			atomic.AddInt64(&calls, 1)
+			ctx.SetBodyString(`{"trip": {"summary": {"length": 12.5}}}`)
+		}},
+	)
+
+	input := NewRoute().From(48.390394, -4.486076).To(48.45252, -4.25252).Costing(CostingModelAuto).Build()
+
+	for i, expected := range []CacheResult{CacheResultMiss, CacheResultHit, CacheResultHit} {
+		result := CacheResultNone
+
+		output, err := clt.RouteContext(WithCacheResult(context.Background(), &result), input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if output.Trip == nil || output.Trip.Summary == nil || output.Trip.Summary.Length == nil {
+			t.Fatalf("request %d: unexpected output %+v", i, output)
+		}
+
+		if result != expected {
+			t.Fatalf("request %d: expected cache result %s, got %s", i, expected, result)
+		}
+	}
+
+	if n := atomic.LoadInt64(&calls); n != 1 {
+		t.Fatalf("expected 1 call to valhalla, got %d", n)
+	}
+
+	result := CacheResultNone
+	if _, err := clt.RouteContext(WithCacheResult(WithCacheBypass(context.Background()), &result), input); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := atomic.LoadInt64(&calls); result != CacheResultBypass || n != 2 {
+		t.Fatalf("expected bypass to call valhalla, got %s and %d calls", result, n)
+	}
+
+	input.DateTime = Now()
+	for i := 0; i < 2; i++ {
+		if _, err := clt.RouteContext(WithCacheResult(context.Background(), &result), input); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := atomic.LoadInt64(&calls); result != CacheResultUncacheable || n != 4 {
+		t.Fatalf("expected current date time not to be cached, got %s and %d calls", result, n)
+	}
+
+	if stats := clt.CacheStats(); stats.Hits != 2 || stats.Misses != 1 || stats.Errors != 0 {
+		t.Fatalf("unexpected cache stats %+v", stats)
+	}
+}
+
+func TestClientCacheDecodeError(t *testing.T) {
+	calls := int64(0)
+	cache := NewMemoryCache(MemoryCacheConfig{})
+
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{Endpoint: "http://valhalla.local", Cache: &CacheConfig{Backend: cache}},
+		map[string]fasthttp.RequestHandler{"valhalla.local": func(ctx *fasthttp.RequestCtx) {
+			if atomic.AddInt64(&calls, 1) == 1 {
+				ctx.SetBodyString(`{"trip": "invalid"}`)
+				return
+			}
+
+			ctx.SetBodyString(`{"trip": {"summary": {"length": 12.5}}}`)
+		}},
+	)
+
+	input := NewRoute().From(48.390394, -4.486076).To(48.45252, -4.25252).Costing(CostingModelAuto).Build()
+	if _, err := clt.Route(input); err == nil {
+		t.Fatal("expected decode error")
+	}
+
+	if cache.Len() != 0 {
+		t.Fatal("expected response failing to decode not to be cached")
+	}
+
+	// Cached values failing to decode are requested again and overwritten
+	body, _ := json.Marshal(input)
+	key, _ := cacheKey(&CacheConfig{}, ActionRoute, body)
+	cache.Set(context.Background(), key, []byte(`j{"trip": "invalid"}`)) //nolint:errcheck
+
+	result := CacheResultNone
+	output, err := clt.RouteContext(WithCacheResult(context.Background(), &result), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != CacheResultMiss || *output.Trip.Summary.Length != 12.5 || atomic.LoadInt64(&calls) != 2 {
+		t.Fatalf("expected invalid cached value to be a miss, got %s, %+v and %d calls", result, output, atomic.LoadInt64(&calls))
+	}
+
+	if _, err := clt.Route(input); err != nil || atomic.LoadInt64(&calls) != 2 {
+		t.Fatalf("expected overwritten value to be a hit, got %v and %d calls", err, atomic.LoadInt64(&calls))
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	cfg := &CacheConfig{}
+
+	key1, ok1 := cacheKey(cfg, ActionRoute, []byte(`{"costing": "auto", "units": "km"}`))
+	key2, ok2 := cacheKey(cfg, ActionRoute, []byte(`{"units":"km","costing":"auto"}`))
+	if !ok1 || !ok2 || key1 != key2 {
+		t.Fatalf("expected equivalent bodies to have the same key, got %q and %q", key1, key2)
+	}
+
+	if key3, _ := cacheKey(cfg, ActionMatrix, []byte(`{"costing": "auto", "units": "km"}`)); key3 == key1 {
+		t.Fatal("expected actions to have different keys")
+	}
+
+	if _, ok := cacheKey(&CacheConfig{Actions: []string{ActionMatrix}}, ActionRoute, []byte(`{}`)); ok {
+		t.Fatal("expected action not configured to be uncacheable")
+	}
+
+	body := []byte(`{"date_time": {"type": 0}}`)
+	if _, ok := cacheKey(cfg, ActionRoute, body); ok {
+		t.Fatal("expected current date time to be uncacheable")
+	}
+
+	if _, ok := cacheKey(&CacheConfig{CacheCurrentDateTime: true}, ActionRoute, body); !ok {
+		t.Fatal("expected current date time to be cacheable when configured")
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(MemoryCacheConfig{MaxEntries: 2, MaxBytes: 10})
+
+	cache.Set(ctx, "a", []byte("aaa")) //nolint:errcheck
+	cache.Set(ctx, "b", []byte("bbb")) //nolint:errcheck
+	cache.Get(ctx, "a")                //nolint:errcheck
+	cache.Set(ctx, "c", []byte("ccc")) //nolint:errcheck
+
+	if _, found, _ := cache.Get(ctx, "b"); found {
+		t.Fatal("expected least recently used entry to be evicted")
+	}
+
+	if value, found, _ := cache.Get(ctx, "a"); !found || string(value) != "aaa" {
+		t.Fatalf("expected a to be cached, got %q", value)
+	}
+
+	cache.Set(ctx, "d", []byte("dddddddd")) //nolint:errcheck
+	if cache.Len() != 1 {
+		t.Fatalf("expected entries to be evicted by size, got %d entries", cache.Len())
+	}
+
+	cache.Set(ctx, "e", []byte("eeeeeeeeeee")) //nolint:errcheck
+	if _, found, _ := cache.Get(ctx, "e"); found {
+		t.Fatal("expected value larger than max bytes not to be stored")
+	}
+
+	cache.Set(ctx, "d", []byte("ddddddddddd")) //nolint:errcheck
+	if _, found, _ := cache.Get(ctx, "d"); found {
+		t.Fatal("expected previous value to be removed by a value larger than max bytes")
+	}
+
+	cache = NewMemoryCache(MemoryCacheConfig{TTL: time.Millisecond})
+	cache.Set(ctx, "a", []byte("aaa")) //nolint:errcheck
+	time.Sleep(2 * time.Millisecond)
+
+	if _, found, _ := cache.Get(ctx, "a"); found || cache.Len() != 0 {
+		t.Fatal("expected expired entry to be removed")
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "cache")
+
+	cache, err := NewDiskCache(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found, err := cache.Get(ctx, "route:abc"); found || err != nil {
+		t.Fatalf("expected missing entry, got %v, %v", found, err)
+	}
+
+	if err := cache.Set(ctx, "route:abc", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	value, found, err := cache.Get(ctx, "route:abc")
+	if err != nil || !found || string(value) != "value" {
+		t.Fatalf("expected cached value, got %q, %v, %v", value, found, err)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(cache.path("route:abc"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found, _ := cache.Get(ctx, "route:abc"); found {
+		t.Fatal("expected expired entry not to be found")
+	}
+
+	// Temporary files of interrupted writes
+	for _, name := range []string{diskCacheTempPrefix + "old", diskCacheTempPrefix + "recent"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("partial"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Chtimes(filepath.Join(dir, diskCacheTempPrefix+"old"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cache.Prune(); err != nil {
+		t.Fatal(err)
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 || entries[0].Name() != diskCacheTempPrefix+"recent" {
+		t.Fatalf("expected expired entries and old temporary files to be pruned, got %v", entries)
+	}
+}
+
+func TestClientDiskCache(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := int64(0)
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{Endpoint: "http://valhalla.local", Cache: &CacheConfig{Backend: cache}},
+		map[string]fasthttp.RequestHandler{"valhalla.local": func(ctx *fasthttp.RequestCtx) {
+			atomic.AddInt64(&calls, 1)
+			ctx.SetBodyString(`{"trip": {"summary": {"length": 12.5}}}`)
+		}},
+	)
+	input := NewRoute().From(48.390394, -4.486076).To(48.45252, -4.25252).Costing(CostingModelAuto).Build()
+
+	for i := 0; i < 2; i++ {
+		if _, err := clt.Route(input); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := atomic.LoadInt64(&calls); n != 1 {
+		t.Fatalf("expected 1 call to valhalla, got %d", n)
+	}
+}
@@ -0,0 +1,266 @@
+package client
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCircuitFailureRatio     = 0.5
+	defaultCircuitMinRequests      = 10
+	defaultCircuitWindow           = 10 * time.Second
+	defaultCircuitCooldown         = 30 * time.Second
+	defaultCircuitHalfOpenRequests = 1
+)
+
+// ErrCircuitOpen is returned without sending the request when the circuit breakers
+// of the endpoints are open
+var ErrCircuitOpen = errors.New("valhalla endpoint circuit breaker is open")
+
+// CircuitState state of the circuit breaker of an endpoint
+type CircuitState int
+
+// Circuit breaker states
+const (
+	// CircuitClosed requests are sent, failures are counted
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen requests fail fast with ErrCircuitOpen until the cool-down is over
+	CircuitOpen
+
+	// CircuitHalfOpen a limited number of probe requests are sent: the circuit closes
+	// if they succeed, opens again if one fails
+	CircuitHalfOpen
+)
+
+var circuitStateNames = []string{"closed", "open", "half-open"}
+
+// String returns the name of the state
+func (state CircuitState) String() string {
+	if state < 0 || int(state) >= len(circuitStateNames) {
+		return "unknown"
+	}
+
+	return circuitStateNames[state]
+}
+
+// CircuitBreakerConfig configures the circuit breaker of each endpoint.
+// Failures are transport errors and 5xx responses, requests cancelled by the caller are not counted.
+type CircuitBreakerConfig struct {
+	// FailureRatio ratio of failed requests opening the circuit, from 0 to 1. Default 0.5.
+	FailureRatio float64 `json:"failure_ratio" yaml:"failure_ratio"`
+
+	// MinRequests minimum number of requests in the window before the ratio is evaluated. Default 10.
+	MinRequests int `json:"min_requests" yaml:"min_requests"`
+
+	// Window duration over which requests are counted, counters are reset at the end of each window.
+	// Default 10s.
+	Window time.Duration `json:"window" yaml:"window"`
+
+	// Cooldown duration the circuit stays open before probe requests are sent. Default 30s.
+	Cooldown time.Duration `json:"cooldown" yaml:"cooldown"`
+
+	// HalfOpenRequests number of concurrent probe requests of a half-open circuit. Default 1.
+	HalfOpenRequests int `json:"half_open_requests" yaml:"half_open_requests"`
+
+	// OnStateChange (optional) called when the circuit of an endpoint changes state.
+	OnStateChange func(endpoint string, from, to CircuitState) `json:"-" yaml:"-"`
+}
+
+// circuitBreaker the circuit breaker of an endpoint
+type circuitBreaker struct {
+	endpoint string
+
+	failureRatio     float64
+	minRequests      int
+	window           time.Duration
+	cooldown         time.Duration
+	halfOpenRequests int
+	onStateChange    func(endpoint string, from, to CircuitState)
+
+	mu          sync.Mutex
+	state       CircuitState
+	generation  uint64
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int
+}
+
+// newCircuitBreaker returns the circuit breaker of endpoint configured by cfg, nil if cfg is nil
+func newCircuitBreaker(endpoint string, cfg *CircuitBreakerConfig) *circuitBreaker {
+	if cfg == nil {
+		return nil
+	}
+
+	breaker := &circuitBreaker{
+		endpoint:         endpoint,
+		failureRatio:     cfg.FailureRatio,
+		minRequests:      cfg.MinRequests,
+		window:           cfg.Window,
+		cooldown:         cfg.Cooldown,
+		halfOpenRequests: cfg.HalfOpenRequests,
+		onStateChange:    cfg.OnStateChange,
+		windowStart:      time.Now(),
+	}
+
+	if breaker.failureRatio <= 0 {
+		breaker.failureRatio = defaultCircuitFailureRatio
+	}
+
+	if breaker.minRequests <= 0 {
+		breaker.minRequests = defaultCircuitMinRequests
+	}
+
+	if breaker.window <= 0 {
+		breaker.window = defaultCircuitWindow
+	}
+
+	if breaker.cooldown <= 0 {
+		breaker.cooldown = defaultCircuitCooldown
+	}
+
+	if breaker.halfOpenRequests <= 0 {
+		breaker.halfOpenRequests = defaultCircuitHalfOpenRequests
+	}
+
+	return breaker
+}
+
+// current returns the current state of the circuit
+func (breaker *circuitBreaker) current() CircuitState {
+	breaker.mu.Lock()
+	from := breaker.state
+	to := breaker.refresh(time.Now())
+	breaker.mu.Unlock()
+
+	breaker.notify(from, to)
+
+	return to
+}
+
+// ready returns true if a request may be sent, without reserving it
+func (breaker *circuitBreaker) ready() bool {
+	breaker.mu.Lock()
+	from := breaker.state
+	to := breaker.refresh(time.Now())
+	ready := to == CircuitClosed || (to == CircuitHalfOpen && breaker.probes < breaker.halfOpenRequests)
+	breaker.mu.Unlock()
+
+	breaker.notify(from, to)
+
+	return ready
+}
+
+// circuitTicket identifies a request allowed by a circuit breaker
+type circuitTicket struct {
+	// generation of the circuit state when the request was allowed
+	generation uint64
+
+	// probe true if the request is a probe of a half-open circuit
+	probe bool
+}
+
+// allow reserves a request, returns false if the circuit rejects it.
+// An allowed request must be completed by done with the returned ticket.
+func (breaker *circuitBreaker) allow() (circuitTicket, bool) {
+	breaker.mu.Lock()
+	from := breaker.state
+	to := breaker.refresh(time.Now())
+
+	ticket := circuitTicket{generation: breaker.generation}
+	allowed := to == CircuitClosed
+	if to == CircuitHalfOpen && breaker.probes < breaker.halfOpenRequests {
+		breaker.probes++
+		ticket.probe = true
+		allowed = true
+	}
+	breaker.mu.Unlock()
+
+	breaker.notify(from, to)
+
+	return ticket, allowed
+}
+
+// done completes the request of ticket. counted is false if its result must be ignored
+// (ie: cancelled by the caller), failed is true if it failed.
+// Results of requests allowed before the last state change are ignored.
+func (breaker *circuitBreaker) done(ticket circuitTicket, counted, failed bool) {
+	now := time.Now()
+
+	breaker.mu.Lock()
+	from := breaker.state
+	breaker.refresh(now)
+
+	switch {
+	case ticket.generation != breaker.generation:
+	case ticket.probe:
+		breaker.probes--
+
+		switch {
+		case !counted:
+		case failed:
+			breaker.open(now)
+		default:
+			breaker.close(now)
+		}
+	case breaker.state == CircuitClosed && counted:
+		breaker.requests++
+		if failed {
+			breaker.failures++
+		}
+
+		if breaker.requests >= breaker.minRequests &&
+			float64(breaker.failures) >= breaker.failureRatio*float64(breaker.requests) {
+			breaker.open(now)
+		}
+	}
+
+	to := breaker.state
+	breaker.mu.Unlock()
+
+	breaker.notify(from, to)
+}
+
+// refresh updates the state and the window at now, returns the state. The lock must be held.
+func (breaker *circuitBreaker) refresh(now time.Time) CircuitState {
+	switch breaker.state {
+	case CircuitOpen:
+		if !now.Before(breaker.openedAt.Add(breaker.cooldown)) {
+			breaker.state = CircuitHalfOpen
+			breaker.generation++
+			breaker.probes = 0
+		}
+	case CircuitClosed:
+		if !now.Before(breaker.windowStart.Add(breaker.window)) {
+			breaker.windowStart = now
+			breaker.requests, breaker.failures = 0, 0
+		}
+	}
+
+	return breaker.state
+}
+
+// open opens the circuit, the lock must be held
+func (breaker *circuitBreaker) open(now time.Time) {
+	breaker.state = CircuitOpen
+	breaker.generation++
+	breaker.openedAt = now
+}
+
+// close closes the circuit with a new window, the lock must be held
+func (breaker *circuitBreaker) close(now time.Time) {
+	breaker.state = CircuitClosed
+	breaker.generation++
+	breaker.windowStart = now
+	breaker.requests, breaker.failures = 0, 0
+}
+
+// notify calls the state change callback if the state changed, the lock must not be held
+func (breaker *circuitBreaker) notify(from, to CircuitState) {
+	if from != to && breaker.onStateChange != nil {
+		breaker.onStateChange(breaker.endpoint, from, to)
+	}
+}
@@ -0,0 +1,210 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestCircuitBreakerStates(t *testing.T) {
+	transitions := []string{}
+	breaker := newCircuitBreaker("http://valhalla.local", &CircuitBreakerConfig{
+		FailureRatio: 0.5,
+		MinRequests:  4,
+		Window:       time.Minute,
+		Cooldown:     20 * time.Millisecond,
+		OnStateChange: func(endpoint string, from, to CircuitState) {
+			transitions = append(transitions, fmt.Sprintf("%s>%s", from, to))
+		},
+	})
+
+	for _, failed := range []bool{false, true, false, true} {
+		ticket, allowed := breaker.allow()
+		if !allowed {
+			t.Fatal("expected closed circuit to allow requests")
+		}
+
+		breaker.done(ticket, true, failed)
+	}
+
+	if _, allowed := breaker.allow(); breaker.current() != CircuitOpen || allowed {
+		t.Fatalf("expected circuit to open at failure ratio, got %s", breaker.current())
+	}
+
+	time.Sleep(25 * time.Millisecond)
+
+	// Only one probe at a time, its failure opens the circuit again
+	probe, allowed := breaker.allow()
+	if _, again := breaker.allow(); !allowed || again {
+		t.Fatal("expected half-open circuit to allow one probe")
+	}
+
+	breaker.done(probe, true, true)
+
+	time.Sleep(25 * time.Millisecond)
+
+	// A cancelled probe is not a result
+	probe, _ = breaker.allow()
+	breaker.done(probe, false, false)
+
+	probe, allowed = breaker.allow()
+	if breaker.current() != CircuitHalfOpen || !allowed {
+		t.Fatal("expected cancelled probe to free the half-open circuit")
+	}
+
+	breaker.done(probe, true, false)
+
+	expected := []string{"closed>open", "open>half-open", "half-open>open", "open>half-open", "half-open>closed"}
+	if fmt.Sprint(transitions) != fmt.Sprint(expected) {
+		t.Fatalf("expected transitions %v, got %v", expected, transitions)
+	}
+}
+
+func TestCircuitBreakerWindow(t *testing.T) {
+	breaker := newCircuitBreaker("http://valhalla.local", &CircuitBreakerConfig{MinRequests: 2, Window: 20 * time.Millisecond})
+
+	ticket, _ := breaker.allow()
+	breaker.done(ticket, true, true)
+
+	time.Sleep(25 * time.Millisecond)
+
+	ticket, _ = breaker.allow()
+	breaker.done(ticket, true, true)
+
+	if breaker.current() != CircuitClosed {
+		t.Fatal("expected failures of previous window to be reset")
+	}
+}
+
+func TestCircuitBreakerStaleRequest(t *testing.T) {
+	breaker := newCircuitBreaker("http://valhalla.local", &CircuitBreakerConfig{
+		MinRequests: 2,
+		Window:      time.Minute,
+		Cooldown:    20 * time.Millisecond,
+	})
+
+	// Slow request allowed while the circuit is closed
+	stale, _ := breaker.allow()
+
+	for i := 0; i < 2; i++ {
+		ticket, _ := breaker.allow()
+		breaker.done(ticket, true, true)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+
+	probe, allowed := breaker.allow()
+	if !allowed || breaker.current() != CircuitHalfOpen {
+		t.Fatal("expected half-open circuit to allow a probe")
+	}
+
+	// The slow request completing does not close the circuit nor free the probe
+	breaker.done(stale, true, false)
+
+	if _, allowed := breaker.allow(); allowed || breaker.current() != CircuitHalfOpen {
+		t.Fatalf("expected stale result to be ignored, got %s", breaker.current())
+	}
+
+	breaker.done(probe, true, false)
+
+	if breaker.current() != CircuitClosed {
+		t.Fatalf("expected probe to close the circuit, got %s", breaker.current())
+	}
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	calls := int64(0)
+	healthy := int32(0)
+
+	mu := sync.Mutex{}
+	states := []CircuitState{}
+
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{
+			Endpoint: "http://valhalla.local",
+			CircuitBreaker: &CircuitBreakerConfig{
+				MinRequests: 3,
+				Cooldown:    20 * time.Millisecond,
+				OnStateChange: func(endpoint string, from, to CircuitState) {
+					mu.Lock()
+					defer mu.Unlock()
+					states = append(states, to)
+				},
+			},
+		},
+		map[string]fasthttp.RequestHandler{"valhalla.local": func(ctx *fasthttp.RequestCtx) {
+			atomic.AddInt64(&calls, 1)
+			if atomic.LoadInt32(&healthy) == 0 {
+				ctx.Error("tiles are being rebuilt", fasthttp.StatusServiceUnavailable)
+				return
+			}
+
+			ctx.SetBodyString(`{}`)
+		}},
+	)
+
+	for i := 0; i < 3; i++ {
+		if _, err := clt.Route(&RouteInput{}); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("expected error response, got %v", err)
+		}
+	}
+
+	if _, err := clt.Route(&RouteInput{}); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected circuit open error, got %v", err)
+	}
+
+	if calls != 3 || clt.Endpoints()[0].CircuitState() != CircuitOpen {
+		t.Fatalf("expected open circuit to fail fast, got %d calls", calls)
+	}
+
+	atomic.StoreInt32(&healthy, 1)
+	time.Sleep(25 * time.Millisecond)
+
+	if _, err := clt.Route(&RouteInput{}); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if fmt.Sprint(states) != fmt.Sprint([]CircuitState{CircuitOpen, CircuitHalfOpen, CircuitClosed}) {
+		t.Fatalf("unexpected state changes %v", states)
+	}
+}
+
+func TestClientCircuitBreakerPool(t *testing.T) {
+	callsA, callsB := int64(0), int64(0)
+
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{
+			Endpoints:      []*EndpointConfig{{URL: "http://a.local"}, {URL: "http://b.local"}},
+			EndpointHealth: &EndpointHealthConfig{MaxFailures: 100},
+			CircuitBreaker: &CircuitBreakerConfig{MinRequests: 2, Cooldown: time.Minute},
+		},
+		map[string]fasthttp.RequestHandler{
+			"a.local": func(ctx *fasthttp.RequestCtx) {
+				atomic.AddInt64(&callsA, 1)
+				ctx.Error("internal error", fasthttp.StatusInternalServerError)
+			},
+			"b.local": func(ctx *fasthttp.RequestCtx) {
+				atomic.AddInt64(&callsB, 1)
+				ctx.SetBodyString(`{}`)
+			},
+		},
+	)
+
+	for i := 0; i < 10; i++ {
+		clt.Route(&RouteInput{}) //nolint:errcheck
+	}
+
+	if callsA != 2 || callsB != 8 {
+		t.Fatalf("expected requests to skip the open endpoint, got %d and %d calls", callsA, callsB)
+	}
+}
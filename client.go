@@ -1,30 +1,56 @@
 package client
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/goccy/go-json"
 	"github.com/valyala/fasthttp"
 )
 
+// Valhalla actions, used as request path
+const (
+	ActionRoute           string = "route"
+	ActionOptimizedRoute  string = "optimized_route"
+	ActionMatrix          string = "sources_to_targets"
+	ActionIsochrone       string = "isochrone"
+	ActionElevation       string = "height"
+	ActionLocate          string = "locate"
+	ActionTraceRoute      string = "trace_route"
+	ActionTraceAttributes string = "trace_attributes"
+)
+
 // BeforeRequestFn is a function that can be called before sending a request
 // allowing to customize request before it is sent
 type BeforeRequestFn func(req *fasthttp.Request) error
 
-// Client is the client for the valhalla service
+// Client is the client for the valhalla service.
+// Requests of the Context methods are abandoned, not interrupted, when their context is done:
+// see ClientConfig.ReadTimeout to bound the connections they hold.
 type Client struct {
 	config          *ClientConfig
 	httpClient      *fasthttp.Client
 	beforeRequestFn BeforeRequestFn
+	endpoints       *endpointPool
+	middlewares     []Middleware
+	cacheCounters   cacheCounters
+	flights         flightGroup
+	hedger          *hedger
 }
 
 // NewClient creates a new client with given config cfg
 func NewClient(cfg *ClientConfig) *Client {
-	clt := &Client{config: cfg}
+	clt := &Client{config: cfg, endpoints: newEndpointPool(cfg), hedger: newHedger(cfg.Hedging)}
 
 	httpClient := &fasthttp.Client{
-		Name:      "valhalla-http-client-go",
-		TLSConfig: cfg.TLSConfig,
+		Name:         "valhalla-http-client-go",
+		TLSConfig:    cfg.TLSConfig,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
 	}
 	clt.httpClient = httpClient
 
@@ -36,24 +62,45 @@ func (client *Client) GetFastHTTPClient() *fasthttp.Client {
 	return client.httpClient
 }
 
+// Endpoints returns the endpoints of the client pool
+func (client *Client) Endpoints() []*Endpoint {
+	return client.endpoints.endpoints
+}
+
 // BeforeRequest allow caller to customize fasthttp request object (ex: adding headers, ...)
+//
+// Deprecated: use Use with a Middleware, which can also inspect the response.
 func (client *Client) BeforeRequest(fn BeforeRequestFn) {
 	client.beforeRequestFn = fn
 }
 
-// buildBaseRequest for given method and path
+// buildBaseRequest for given method, endpoint and path
 func (client *Client) buildBaseRequest(
-	method, path string,
-	body interface{},
+	ctx context.Context,
+	method, endpoint, path string,
+	body []byte,
 ) (*fasthttp.Request, error) {
 	req := fasthttp.AcquireRequest()
 
 	// Set uri
-	if err := req.URI().Parse(nil, []byte(client.config.Endpoint+"/"+path)); err != nil {
+	if err := req.URI().Parse(nil, []byte(endpoint+"/"+path)); err != nil {
 		fasthttp.ReleaseRequest(req)
 		return nil, fmt.Errorf("unable to build request uri: %w", err)
 	}
 
+	req.Header.SetMethod(method)
+
+	for key, value := range client.config.CustomHeaders {
+		req.Header.Set(key, value)
+	}
+
+	if client.config.Auth != nil {
+		if err := client.config.Auth.apply(ctx, req); err != nil {
+			fasthttp.ReleaseRequest(req)
+			return nil, err
+		}
+	}
+
 	if client.beforeRequestFn != nil {
 		if err := client.beforeRequestFn(req); err != nil {
 			fasthttp.ReleaseRequest(req)
@@ -63,13 +110,7 @@ func (client *Client) buildBaseRequest(
 
 	// Set request body
 	if body != nil {
-		bodyBytes, err := json.Marshal(body)
-		if err != nil {
-			fasthttp.ReleaseRequest(req)
-			return nil, fmt.Errorf("error while encoding body to json: %w", err)
-		}
-
-		req.SetBody(bodyBytes)
+		req.SetBody(body)
 	}
 
 	// We send json
@@ -77,3 +118,304 @@ func (client *Client) buildBaseRequest(
 
 	return req, nil
 }
+
+// call sends input to given valhalla action and decodes the json response into output.
+// Failed attempts are retried according to the client retry policy.
+func (client *Client) call(ctx context.Context, action string, input, output interface{}) error {
+	if v, ok := input.(validator); ok && client.config.ValidateInputs {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", action, err)
+		}
+	}
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("error while encoding %s body to json: %w", action, err)
+	}
+
+	if cache := client.config.Cache; cache != nil && cache.Backend != nil {
+		return client.cachedSend(ctx, cache, action, body, output)
+	}
+
+	reportCacheResult(ctx, CacheResultNone)
+
+	return client.send(ctx, action, body, output)
+}
+
+// send sends the request body of action, sharing the response with the identical
+// concurrent requests if coalescing is enabled
+func (client *Client) send(ctx context.Context, action string, body []byte, output interface{}) error {
+	if client.config.CoalesceRequests {
+		return client.coalescedSend(ctx, action, body, output)
+	}
+
+	return client.retry(ctx, action, body, output)
+}
+
+// retry sends the request body of action, retrying it according to the retry policy
+func (client *Client) retry(ctx context.Context, action string, body []byte, output interface{}) error {
+	policy := client.config.RetryPolicy
+
+	for attempt := 1; ; attempt++ {
+		var transport bool
+		var err error
+
+		if client.hedger != nil && client.hedger.policy.hedged(action) {
+			transport, err = client.hedgedAttempt(ctx, action, attempt, body, output)
+		} else {
+			transport, err = client.attempt(ctx, newEndpointSet(), action, attempt, body, output)
+		}
+
+		if err == nil {
+			return nil
+		}
+
+		if policy == nil {
+			return err
+		}
+
+		if attempt >= policy.MaxAttempts || !policy.retryable(err, transport) {
+			return &RetryError{Attempts: attempt, Err: err}
+		}
+
+		// Retry-After of the response is honoured if longer than the backoff
+		if err := policy.wait(ctx, attempt, retryAfter(err)); err != nil {
+			return &RetryError{Attempts: attempt, Err: err}
+		}
+	}
+}
+
+// attempt sends body to given valhalla action and decodes the json response into output.
+// The request fails over to the other endpoints of the pool not in tried on transport errors.
+// transport is true when the error comes from the http layer (no response received).
+func (client *Client) attempt(
+	ctx context.Context,
+	tried *endpointSet,
+	action string,
+	attempt int,
+	body []byte,
+	output interface{},
+) (transport bool, err error) {
+	endpoint := tried.pick(client.endpoints)
+	if endpoint == nil {
+		// Endpoints are only skipped before any attempt when their circuits are open
+		if client.config.CircuitBreaker != nil {
+			return false, ErrCircuitOpen
+		}
+
+		return false, ErrNoEndpointAvailable
+	}
+
+	return client.attemptFrom(ctx, tried, endpoint, action, attempt, body, output)
+}
+
+// attemptFrom is attempt starting with endpoint, already added to tried
+func (client *Client) attemptFrom(
+	ctx context.Context,
+	tried *endpointSet,
+	endpoint *Endpoint,
+	action string,
+	attempt int,
+	body []byte,
+	output interface{},
+) (transport bool, err error) {
+	for {
+		transport, err = client.attemptEndpoint(ctx, endpoint, action, attempt, body, output)
+		if !transport || ctx.Err() != nil {
+			return transport, err
+		}
+
+		if endpoint = tried.pick(client.endpoints); endpoint == nil {
+			return transport, err
+		}
+	}
+}
+
+// attemptEndpoint sends body to given valhalla action of endpoint once, through
+// the middlewares chain, and decodes the json response into output.
+func (client *Client) attemptEndpoint(
+	ctx context.Context,
+	endpoint *Endpoint,
+	action string,
+	attempt int,
+	body []byte,
+	output interface{},
+) (transport bool, err error) {
+	req, err := client.buildBaseRequest(ctx, fasthttp.MethodPost, endpoint.URL(), action, body)
+	if err != nil {
+		return false, fmt.Errorf("failed to build request for %s: %w", action, err)
+	}
+	defer fasthttp.ReleaseRequest(req)
+
+	// Acquire response
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	rt := &RoundTrip{
+		Action:   action,
+		Endpoint: endpoint.URL(),
+		Attempt:  attempt,
+		Request:  req,
+		Response: resp,
+	}
+
+	var ticket circuitTicket
+	if endpoint.breaker != nil {
+		var allowed bool
+		if ticket, allowed = endpoint.breaker.allow(); !allowed {
+			return true, fmt.Errorf("error while calling http %s service: %w", action, ErrCircuitOpen)
+		}
+	}
+
+	release, err := endpoint.throttle.acquire(ctx)
+	if err != nil {
+		if endpoint.breaker != nil {
+			endpoint.breaker.done(ticket, false, false)
+		}
+
+		return false, fmt.Errorf("error while waiting to call http %s service: %w", action, err)
+	}
+
+	atomic.AddInt64(&endpoint.inFlight, 1)
+	err = client.roundTripper()(ctx, rt)
+	atomic.AddInt64(&endpoint.inFlight, -1)
+	release()
+
+	if resp.StatusCode() == fasthttp.StatusTooManyRequests {
+		if delay := parseRetryAfter(resp.Header.Peek(fasthttp.HeaderRetryAfter)); delay > 0 {
+			endpoint.throttle.pause(delay)
+		}
+	}
+
+	// Track endpoint health and circuit, requests cancelled by caller are not endpoint failures
+	counted := ctx.Err() == nil
+	failed := rt.transportErr || resp.StatusCode() >= fasthttp.StatusInternalServerError
+
+	switch {
+	case !counted:
+	case failed:
+		endpoint.failed(client.config.EndpointHealth)
+	default:
+		endpoint.succeeded()
+	}
+
+	if endpoint.breaker != nil {
+		endpoint.breaker.done(ticket, counted, failed)
+	}
+
+	if err != nil {
+		return rt.transportErr, err
+	}
+
+	// Extract response
+	isPBF := bytes.HasPrefix(resp.Header.ContentType(), []byte(pbfContentType))
+
+	return false, decodeOutput(action, isPBF, resp.Body(), output)
+}
+
+// rawOutput receives a copy of the undecoded response body of a request
+type rawOutput struct {
+	pbf  bool
+	body []byte
+}
+
+// decodeOutput decodes the json or pbf response body into output
+func decodeOutput(action string, isPBF bool, body []byte, output interface{}) error {
+	if raw, ok := output.(*rawOutput); ok {
+		raw.pbf = isPBF
+		raw.body = append(raw.body[:0], body...)
+
+		return nil
+	}
+
+	if unmarshaler, ok := output.(pbfUnmarshaler); ok && isPBF {
+		if err := unmarshaler.unmarshalPBF(body); err != nil {
+			return fmt.Errorf("error while decoding http %s pbf response data: %w", action, err)
+		}
+
+		return nil
+	}
+
+	if err := json.Unmarshal(body, output); err != nil {
+		return fmt.Errorf("error while decoding http %s json response data: %w", action, err)
+	}
+
+	return nil
+}
+
+// roundTrip is the innermost RoundTripper: it sends the request and decodes error responses
+func (client *Client) roundTrip(ctx context.Context, rt *RoundTrip) error {
+	if err := client.do(ctx, rt.Request, rt.Response); err != nil {
+		rt.transportErr = true
+		return fmt.Errorf("error while calling http %s service: %w", rt.Action, err)
+	}
+
+	if rt.Response.StatusCode() != fasthttp.StatusOK {
+		return newErrorResponse(rt)
+	}
+
+	return nil
+}
+
+// do sends req and fills resp, honouring ctx deadline and cancellation.
+// Returned error is ctx error when ctx is done before the response is received.
+func (client *Client) do(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Context can't be cancelled, no need to watch it
+	if ctx.Done() == nil {
+		return client.httpClient.Do(req, resp)
+	}
+
+	// fasthttp does not support cancellation: the request is sent from copies owned by
+	// a goroutine, so they can be abandoned if ctx is done before the response is received.
+	// The abandoned request holds its connection until the ctx deadline, or the client
+	// ReadTimeout and WriteTimeout when ctx has none.
+	reqCopy := fasthttp.AcquireRequest()
+	respCopy := fasthttp.AcquireResponse()
+	req.CopyTo(reqCopy)
+
+	release := func() {
+		fasthttp.ReleaseRequest(reqCopy)
+		fasthttp.ReleaseResponse(respCopy)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if deadline, ok := ctx.Deadline(); ok {
+			errCh <- client.httpClient.DoDeadline(reqCopy, respCopy, deadline)
+			return
+		}
+
+		errCh <- client.httpClient.Do(reqCopy, respCopy)
+	}()
+
+	select {
+	case err := <-errCh:
+		defer release()
+
+		if err != nil {
+			// DoDeadline may time out slightly before ctx is marked done
+			if errors.Is(err, fasthttp.ErrTimeout) {
+				if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+					return context.DeadlineExceeded
+				}
+			}
+
+			return err
+		}
+
+		respCopy.CopyTo(resp)
+
+		return nil
+	case <-ctx.Done():
+		go func() {
+			<-errCh
+			release()
+		}()
+
+		return ctx.Err()
+	}
+}
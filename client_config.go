@@ -2,6 +2,7 @@ package client
 
 import (
 	"crypto/tls"
+	"time"
 )
 
 // ClientConfig is the configuration for the client
@@ -9,4 +10,55 @@ type ClientConfig struct {
 	CustomHeaders map[string]string `json:"custom_headers" yaml:"custom_headers"`
 	Endpoint      string            `json:"endpoint" yaml:"endpoint"`
 	TLSConfig     *tls.Config
+
+	// ReadTimeout (optional) maximum duration to wait for the response of a request once sent.
+	// fasthttp requests can't be interrupted: a request abandoned when its context is cancelled
+	// keeps its connection until the response is read, or until ReadTimeout when the context has
+	// no deadline. Unlimited if 0.
+	ReadTimeout time.Duration `json:"read_timeout" yaml:"read_timeout"`
+
+	// WriteTimeout (optional) maximum duration to send a request. Unlimited if 0.
+	WriteTimeout time.Duration `json:"write_timeout" yaml:"write_timeout"`
+
+	// Auth (optional) authentication applied to every request.
+	Auth *AuthConfig `json:"auth" yaml:"auth"`
+
+	// Endpoints (optional) pool of valhalla endpoints requests are balanced on.
+	// Endpoint is ignored when set.
+	Endpoints []*EndpointConfig `json:"endpoints" yaml:"endpoints"`
+
+	// Balancer (optional) strategy picking the endpoint of each request.
+	// Default to a RoundRobinBalancer.
+	Balancer Balancer `json:"-" yaml:"-"`
+
+	// EndpointHealth (optional) passive health tracking settings of endpoints.
+	EndpointHealth *EndpointHealthConfig `json:"endpoint_health" yaml:"endpoint_health"`
+
+	// Throttle (optional) traffic limits applied to each endpoint, see EndpointConfig.Throttle.
+	// Requests are not limited if nil. Requests of endpoints responding 429 Too Many Requests
+	// with a Retry-After header are delayed accordingly in all cases.
+	Throttle *ThrottleConfig `json:"throttle" yaml:"throttle"`
+
+	// CircuitBreaker (optional) circuit breaker of each endpoint: requests fail fast with
+	// ErrCircuitOpen while the circuits of all endpoints are open. Disabled if nil.
+	CircuitBreaker *CircuitBreakerConfig `json:"circuit_breaker" yaml:"circuit_breaker"`
+
+	// RetryPolicy (optional) policy applied to retry failed requests.
+	// Requests are not retried if nil.
+	RetryPolicy *RetryPolicy `json:"retry_policy" yaml:"retry_policy"`
+
+	// Hedging (optional) policy sending duplicates of slow requests to other endpoints.
+	// Requests are not hedged if nil.
+	Hedging *HedgingPolicy `json:"hedging" yaml:"hedging"`
+
+	// ValidateInputs validates inputs with their Validate method before sending requests.
+	// Invalid inputs fail with a ValidationError, without any request.
+	ValidateInputs bool `json:"validate_inputs" yaml:"validate_inputs"`
+
+	// Cache (optional) caching of responses. Responses are not cached if nil.
+	Cache *CacheConfig `json:"cache" yaml:"cache"`
+
+	// CoalesceRequests shares one http request between the identical concurrent requests
+	// (same action and body). Each caller receives its own copy of the decoded response.
+	CoalesceRequests bool `json:"coalesce_requests" yaml:"coalesce_requests"`
 }
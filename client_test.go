@@ -1,5 +1,17 @@
 package client
 
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
 func getTestClient() *Client {
 	clt := NewClient(&ClientConfig{
 		Endpoint: "https://valhalla1.openstreetmap.de",
@@ -7,3 +19,134 @@ func getTestClient() *Client {
 
 	return clt
 }
+
+// getLocalTestClient returns a client calling an in memory server using given handler
+func getLocalTestClient(t *testing.T, handler fasthttp.RequestHandler) *Client {
+	return getLocalTestPoolClient(
+		t,
+		&ClientConfig{Endpoint: "http://valhalla.local"},
+		map[string]fasthttp.RequestHandler{"valhalla.local": handler},
+	)
+}
+
+// getLocalTestPoolClient returns a client with config cfg calling in memory servers,
+// handlers are indexed by endpoint host. Dialing a host without handler fails.
+func getLocalTestPoolClient(
+	t *testing.T,
+	cfg *ClientConfig,
+	handlers map[string]fasthttp.RequestHandler,
+) *Client {
+	listeners := map[string]*fasthttputil.InmemoryListener{}
+	for host, handler := range handlers {
+		ln := fasthttputil.NewInmemoryListener()
+		t.Cleanup(func() { ln.Close() })
+
+		go fasthttp.Serve(ln, handler) //nolint:errcheck
+
+		listeners[host] = ln
+	}
+
+	clt := NewClient(cfg)
+	clt.GetFastHTTPClient().Dial = func(addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		ln, ok := listeners[host]
+		if !ok {
+			return nil, &net.OpError{Op: "dial", Net: "memory", Err: errors.New("unknown host " + host)}
+		}
+
+		return ln.Dial()
+	}
+
+	return clt
+}
+
+func TestClientCallContextDeadline(t *testing.T) {
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		time.Sleep(200 * time.Millisecond)
+		ctx.SetBodyString(`{}`)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := clt.RouteContext(ctx, &RouteInput{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded error, got %v", err)
+	}
+}
+
+func TestClientCallContextCanceled(t *testing.T) {
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		time.Sleep(200 * time.Millisecond)
+		ctx.SetBodyString(`{}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, err := clt.ElevationContext(ctx, &ElevationInput{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled error, got %v", err)
+	}
+}
+
+func TestClientCallContextCanceledReadTimeout(t *testing.T) {
+	calls := int64(0)
+
+	ln := fasthttputil.NewInmemoryListener()
+	t.Cleanup(func() { ln.Close() })
+
+	go fasthttp.Serve(ln, func(ctx *fasthttp.RequestCtx) { //nolint:errcheck
+		if atomic.AddInt64(&calls, 1) == 1 {
+			time.Sleep(500 * time.Millisecond)
+		}
+
+		ctx.SetBodyString(`{}`)
+	})
+
+	clt := NewClient(&ClientConfig{Endpoint: "http://valhalla.local", ReadTimeout: 30 * time.Millisecond})
+	clt.GetFastHTTPClient().Dial = func(addr string) (net.Conn, error) {
+		return ln.Dial()
+	}
+	clt.GetFastHTTPClient().MaxConnsPerHost = 1
+	clt.GetFastHTTPClient().MaxConnWaitTimeout = time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	if _, err := clt.RouteContext(ctx, &RouteInput{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled error, got %v", err)
+	}
+
+	// The abandoned request frees the only connection at the read timeout
+	start := time.Now()
+	if _, err := clt.Route(&RouteInput{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed >= 300*time.Millisecond {
+		t.Fatalf("expected abandoned request to be bounded by the read timeout, took %s", elapsed)
+	}
+}
+
+func TestClientCallContext(t *testing.T) {
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		if string(ctx.Path()) != "/"+ActionElevation {
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+			return
+		}
+
+		ctx.SetBodyString(`{"height": [10]}`)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := clt.ElevationContext(ctx, &ElevationInput{}); err != nil {
+		t.Fatal(err)
+	}
+}
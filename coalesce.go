@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// flightGroup tracks the in flight coalesced requests, by action and body
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight a request shared by identical concurrent calls
+type flight struct {
+	key    string
+	done   chan struct{}
+	cancel context.CancelFunc
+
+	// waiters number of calls waiting for the flight, guarded by the group mutex
+	waiters int
+
+	// raw and err are set before done is closed
+	raw rawOutput
+	err error
+}
+
+// coalescedSend sends the request body of action, or waits for the identical request in flight.
+// The shared request is cancelled once all its callers have given up, each caller decodes
+// the response body in its own output.
+func (client *Client) coalescedSend(ctx context.Context, action string, body []byte, output interface{}) error {
+	f := client.flights.join(ctx, action+"\x00"+string(body), func(flightCtx context.Context, f *flight) {
+		f.err = client.retry(flightCtx, action, body, &f.raw)
+	})
+
+	select {
+	case <-f.done:
+		if f.err != nil {
+			return f.err
+		}
+
+		return decodeOutput(action, f.raw.pbf, f.raw.body, output)
+	case <-ctx.Done():
+		client.flights.leave(f)
+		return ctx.Err()
+	}
+}
+
+// join returns the flight of key, started with fn if none is in flight.
+// The flight is sent with the context values of the first caller.
+func (group *flightGroup) join(ctx context.Context, key string, fn func(ctx context.Context, f *flight)) *flight {
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	if f, ok := group.flights[key]; ok {
+		f.waiters++
+		return f
+	}
+
+	if group.flights == nil {
+		group.flights = map[string]*flight{}
+	}
+
+	flightCtx, cancel := context.WithCancel(valuesContext{ctx})
+	f := &flight{key: key, done: make(chan struct{}), cancel: cancel, waiters: 1}
+	group.flights[key] = f
+
+	go func() {
+		defer close(f.done)
+		defer cancel()
+
+		fn(flightCtx, f)
+
+		group.mu.Lock()
+		group.remove(f)
+		group.mu.Unlock()
+	}()
+
+	return f
+}
+
+// leave removes a caller of f, cancelling it if it has no caller left
+func (group *flightGroup) leave(f *flight) {
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	f.waiters--
+	if f.waiters > 0 {
+		return
+	}
+
+	f.cancel()
+	group.remove(f)
+}
+
+// remove removes f from the flights in progress, the lock must be held
+func (group *flightGroup) remove(f *flight) {
+	if group.flights[f.key] == f {
+		delete(group.flights, f.key)
+	}
+}
+
+// valuesContext is a context having the values of its parent, but not its deadline nor cancellation
+type valuesContext struct {
+	parent context.Context
+}
+
+func (ctx valuesContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (ctx valuesContext) Done() <-chan struct{} { return nil }
+
+func (ctx valuesContext) Err() error { return nil }
+
+func (ctx valuesContext) Value(key interface{}) interface{} { return ctx.parent.Value(key) }
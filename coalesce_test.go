@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/paulmach/go.geojson"
+	"github.com/valyala/fasthttp"
+)
+
+// coalesceTestResponse isochrone response of the coalescing tests
+const coalesceTestResponse = `{"type": "FeatureCollection", "features": [{"type": "Feature", "properties": {"contour": 10}, "geometry": {"type": "Point", "coordinates": [-4.48, 48.39]}}]}`
+
+// waitFlightWaiters waits until the only flight of clt has n waiters
+func waitFlightWaiters(t *testing.T, clt *Client, n int) {
+	for start := time.Now(); time.Since(start) < time.Second; time.Sleep(time.Millisecond) {
+		clt.flights.mu.Lock()
+		waiters := 0
+		for _, f := range clt.flights.flights {
+			waiters += f.waiters
+		}
+		clt.flights.mu.Unlock()
+
+		if waiters == n {
+			return
+		}
+	}
+
+	t.Fatalf("expected %d waiters", n)
+}
+
+func TestCoalesceRequests(t *testing.T) {
+	release := make(chan struct{})
+	calls := int64(0)
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{Endpoint: "http://valhalla.local", CoalesceRequests: true},
+		map[string]fasthttp.RequestHandler{"valhalla.local": func(ctx *fasthttp.RequestCtx) {
+			atomic.AddInt64(&calls, 1)
+			<-release
+			ctx.SetBodyString(coalesceTestResponse)
+		}},
+	)
+
+	input := NewIsochrone().At(48.390394, -4.486076).Costing(CostingModelPedestrian).Minutes(10).Build()
+
+	const n = 10
+	outputs := make([]*geojson.FeatureCollection, n)
+	errs := make([]error, n)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			outputs[i], errs[i] = clt.Isochrone(input)
+		}(i)
+	}
+
+	waitFlightWaiters(t, clt, n)
+	close(release)
+	wg.Wait()
+
+	if atomic.LoadInt64(&calls) != 1 {
+		t.Fatalf("expected 1 call to valhalla, got %d", atomic.LoadInt64(&calls))
+	}
+
+	for i := 0; i < n; i++ {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+
+		if len(outputs[i].Features) != 1 {
+			t.Fatalf("unexpected output %+v", outputs[i])
+		}
+	}
+
+	// Callers own their output
+	outputs[0].Features[0].Properties["contour"] = 20
+	if outputs[1].Features[0].Properties["contour"] != 10.0 {
+		t.Fatalf("expected outputs to be copies, got %v", outputs[1].Features[0].Properties["contour"])
+	}
+
+	// Requests are not coalesced once the response is received
+	if _, err := clt.Isochrone(input); err != nil || atomic.LoadInt64(&calls) != 2 {
+		t.Fatalf("expected a new call to valhalla, got %v and %d calls", err, atomic.LoadInt64(&calls))
+	}
+}
+
+func TestCoalesceRequestsCancel(t *testing.T) {
+	release := make(chan struct{})
+	calls := int64(0)
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{Endpoint: "http://valhalla.local", CoalesceRequests: true},
+		map[string]fasthttp.RequestHandler{"valhalla.local": func(ctx *fasthttp.RequestCtx) {
+			atomic.AddInt64(&calls, 1)
+			<-release
+			ctx.SetBodyString(coalesceTestResponse)
+		}},
+	)
+
+	input := NewIsochrone().At(48.390394, -4.486076).Costing(CostingModelPedestrian).Minutes(10).Build()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 2)
+
+	go func() {
+		_, err := clt.IsochroneContext(ctx, input)
+		errCh <- err
+	}()
+
+	waitFlightWaiters(t, clt, 1)
+
+	go func() {
+		_, err := clt.Isochrone(input)
+		errCh <- err
+	}()
+
+	waitFlightWaiters(t, clt, 2)
+
+	// The first caller giving up does not cancel the shared request
+	cancel()
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+
+	close(release)
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+
+	if atomic.LoadInt64(&calls) != 1 {
+		t.Fatalf("expected 1 call to valhalla, got %d", atomic.LoadInt64(&calls))
+	}
+}
@@ -37,6 +37,10 @@ const (
 	// roads when needed, but avoid roads without motor_scooter, moped, or mofa access.
 	CostingModelMotorScooter string = "motor_scooter"
 
+	// CostingModelMotorcycle BETA standard costing for travel by motorcycle. Motorcycle costing
+	// inherits the auto costing behaviors, but checks for motorcycle access on the roads.
+	CostingModelMotorcycle string = "motorcycle"
+
 	// CostingModelMultimodal Currently supports pedestrian and transit.
 	// In the future, multimodal will support a combination of all of the above.
 	CostingModelMultimodal string = "multimodal"
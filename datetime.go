@@ -0,0 +1,132 @@
+package client
+
+import (
+	"time"
+)
+
+// localDateTimeLayout layout of the local date times of valhalla (ISO 8601 without time zone)
+const localDateTimeLayout = "2006-01-02T15:04"
+
+// DateTimeType type of a date time input
+type DateTimeType int
+
+// Date time types
+const (
+	// DateTimeTypeCurrent current departure time
+	DateTimeTypeCurrent DateTimeType = 0
+
+	// DateTimeTypeDepartAt specified departure time
+	DateTimeTypeDepartAt DateTimeType = 1
+
+	// DateTimeTypeArriveBy specified arrival time.
+	// Not yet implemented for multimodal costing method.
+	DateTimeTypeArriveBy DateTimeType = 2
+
+	// DateTimeTypeInvariant invariant specified time, time does not vary over the course of the path.
+	// Not implemented for multimodal or bike share routing.
+	DateTimeTypeInvariant DateTimeType = 3
+)
+
+// DateTime date time of route, matrix and isochrone inputs.
+// Build it with DepartAt, ArriveBy, Now or Invariant.
+type DateTime struct {
+	// Type of date time, one of the DateTimeType values (ie: int(DateTimeTypeDepartAt)).
+	Type *int `json:"type,omitempty"`
+
+	// Value the date and time is specified in ISO 8601 format (YYYY-MM-DDThh:mm)
+	// in the local time zone of departure or arrival.
+	// For example "2016-07-03T08:06"
+	Value *string `json:"value,omitempty"`
+}
+
+// newDateTime returns a date time of type dateTimeType with value t
+func newDateTime(dateTimeType DateTimeType, t time.Time) *DateTime {
+	typeValue, value := int(dateTimeType), t.Format(localDateTimeLayout)
+	return &DateTime{Type: &typeValue, Value: &value}
+}
+
+// DepartAt returns a date time departing at t.
+// Valhalla interprets the value in the time zone of the departure location:
+// t is formatted as its wall clock in its own location, use t.In to convert it before.
+func DepartAt(t time.Time) *DateTime {
+	return newDateTime(DateTimeTypeDepartAt, t)
+}
+
+// ArriveBy returns a date time arriving at t, in the time zone of the arrival location.
+// See DepartAt for time zone handling.
+func ArriveBy(t time.Time) *DateTime {
+	return newDateTime(DateTimeTypeArriveBy, t)
+}
+
+// Invariant returns an invariant date time at t: time does not vary over the course of the path.
+// See DepartAt for time zone handling.
+func Invariant(t time.Time) *DateTime {
+	return newDateTime(DateTimeTypeInvariant, t)
+}
+
+// Now returns a date time departing at the current time, evaluated by valhalla
+// in the time zone of the departure location
+func Now() *DateTime {
+	typeValue := int(DateTimeTypeCurrent)
+	return &DateTime{Type: &typeValue}
+}
+
+// Time returns the value of the date time in loc (UTC if nil).
+// Returns a zero time if the date time has no value (ie: Now).
+func (dateTime *DateTime) Time(loc *time.Location) (time.Time, error) {
+	return parseLocalDateTime(dateTime.Value, loc)
+}
+
+// ArrivalTime returns the expected date time at the location, in the time zone of
+// the location when the response has one (UTC otherwise).
+// Returns a zero time if the location has no date time.
+func (location *RouteLocation) ArrivalTime() (time.Time, error) {
+	return parseLocalDateTime(location.DateTime, timeZoneLocation(location.TimeZoneName, location.TimeZoneOffset))
+}
+
+// ArrivalTime returns the date time at the target, in the time zone of the target
+// when the response has one (UTC otherwise).
+// Returns a zero time if the cell has no date time.
+func (cell *MatrixOutputCell) ArrivalTime() (time.Time, error) {
+	return parseLocalDateTime(cell.DateTime, timeZoneLocation(cell.TimeZoneName, cell.TimeZoneOffset))
+}
+
+// parseLocalDateTime parses value in loc (UTC if nil), returns a zero time if value is nil
+func parseLocalDateTime(value *string, loc *time.Location) (time.Time, error) {
+	if value == nil || *value == "" {
+		return time.Time{}, nil
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	return time.ParseInLocation(localDateTimeLayout, *value, loc)
+}
+
+// timeZoneLocation returns the location of time zone name, or a fixed zone of offset
+// (ie: "+01:00") if the name is unknown. Returns nil if neither can be used.
+func timeZoneLocation(name *string, offset *string) *time.Location {
+	if name != nil && *name != "" {
+		if loc, err := time.LoadLocation(*name); err == nil {
+			return loc
+		}
+	}
+
+	if offset == nil || *offset == "" {
+		return nil
+	}
+
+	t, err := time.Parse("-07:00", *offset)
+	if err != nil {
+		return nil
+	}
+
+	_, seconds := t.Zone()
+	zoneName := *offset
+	if name != nil && *name != "" {
+		zoneName = *name
+	}
+
+	return time.FixedZone(zoneName, seconds)
+}
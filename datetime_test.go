@@ -0,0 +1,63 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gotidy/ptr"
+)
+
+func TestDateTimeConstructors(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	departure := time.Date(2022, 8, 9, 8, 6, 30, 0, loc)
+
+	data, err := json.Marshal(&RouteInput{DateTime: DepartAt(departure)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"date_time":{"type":1,"value":"2022-08-09T08:06"}}` {
+		t.Fatalf("unexpected json %s", data)
+	}
+
+	if *ArriveBy(departure.UTC()).Value != "2022-08-09T07:06" || *Invariant(departure).Type != int(DateTimeTypeInvariant) {
+		t.Fatal("unexpected arrive by or invariant date time")
+	}
+
+	now := Now()
+	if *now.Type != int(DateTimeTypeCurrent) || now.Value != nil {
+		t.Fatalf("unexpected now date time %+v", now)
+	}
+
+	parsed, err := DepartAt(departure).Time(loc)
+	if err != nil || !parsed.Equal(departure.Truncate(time.Minute)) {
+		t.Fatalf("unexpected parsed time %s %v", parsed, err)
+	}
+}
+
+func TestRouteLocationArrivalTime(t *testing.T) {
+	location := &RouteLocation{
+		DateTime:       ptr.String("2022-08-09T10:30"),
+		TimeZoneOffset: ptr.String("+02:00"),
+		TimeZoneName:   ptr.String("Unknown/Zone"),
+	}
+
+	arrival, err := location.ArrivalTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !arrival.Equal(time.Date(2022, 8, 9, 8, 30, 0, 0, time.UTC)) || arrival.Location().String() != "Unknown/Zone" {
+		t.Fatalf("unexpected arrival time %s", arrival)
+	}
+
+	cell := &MatrixOutputCell{DateTime: ptr.String("2022-08-09T10:30")}
+	if arrival, err := cell.ArrivalTime(); err != nil || arrival.Location() != time.UTC || arrival.Hour() != 10 {
+		t.Fatalf("expected utc arrival time without time zone, got %s %v", arrival, err)
+	}
+
+	if arrival, err := (&RouteLocation{}).ArrivalTime(); err != nil || !arrival.IsZero() {
+		t.Fatalf("expected zero arrival time, got %s %v", arrival, err)
+	}
+}
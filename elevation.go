@@ -1,10 +1,9 @@
 package client
 
 import (
-	"fmt"
+	"context"
 
-	"github.com/goccy/go-json"
-	"github.com/valyala/fasthttp"
+	"github.com/angelodlfrtr/valhalla-http-client-go/polyline"
 )
 
 // Point define a geographical point
@@ -55,8 +54,8 @@ type ElevationOutput struct {
 	// Shape contain the specified shape coordinates from the input request.
 	Shape []*ElevationPoint `json:"shape,omitempty"`
 
-	// EncodedPolyline contain the specified encoded polyline,
-	// with six degrees of precision, coordinates from the input request.
+	// EncodedPolyline contain the specified encoded polyline coordinates from the input request,
+	// with the precision of the input shape format.
 	EncodedPolyline *string `json:"encoded_polyline,omitempty"`
 
 	// RangeHeight contain the 2D array of range (x) and height (y) per input latitude,
@@ -70,36 +69,36 @@ type ElevationOutput struct {
 	ID *string `json:"id,omitempty"`
 }
 
-// Elevation returns the elevation for the given input
-func (client *Client) Elevation(input *ElevationInput) (*ElevationOutput, error) {
-	req, err := client.buildBaseRequest(fasthttp.MethodPost, "/height", input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build request for elevation: %w", err)
+// ShapePrecision returns the polyline precision of the shape format,
+// polyline.Precision5 for polyline5 and polyline.Precision6 otherwise
+func (input *ElevationInput) ShapePrecision() int {
+	if input.ShapeFormat != nil && *input.ShapeFormat == "polyline5" {
+		return polyline.Precision5
 	}
-	defer fasthttp.ReleaseRequest(req)
 
-	// Acquire response
-	resp := fasthttp.AcquireResponse()
-	defer fasthttp.ReleaseResponse(resp)
+	return polyline.Precision6
+}
 
-	if err := client.httpClient.Do(req, resp); err != nil {
-		return nil, fmt.Errorf("error while calling http elevation service: %w", err)
+// DecodedPolyline returns the decoded points of the encoded polyline with precision,
+// the one of the input shape format (see ElevationInput.ShapePrecision)
+func (output *ElevationOutput) DecodedPolyline(precision int) ([]polyline.Point, error) {
+	if output.EncodedPolyline == nil {
+		return nil, nil
 	}
 
-	if resp.StatusCode() != fasthttp.StatusOK {
-		errRes := &ErrorResponse{}
-		if err := json.Unmarshal(resp.Body(), errRes); err != nil {
-			errRes.StatusCode = resp.StatusCode()
-			errRes.ErrorMessage = string(resp.Body())
-		}
+	return polyline.Decode(*output.EncodedPolyline, precision)
+}
 
-		return nil, errRes
-	}
+// Elevation returns the elevation for the given input
+func (client *Client) Elevation(input *ElevationInput) (*ElevationOutput, error) {
+	return client.ElevationContext(context.Background(), input)
+}
 
-	// Extract response
+// ElevationContext is like Elevation, the request is cancelled when ctx is done.
+func (client *Client) ElevationContext(ctx context.Context, input *ElevationInput) (*ElevationOutput, error) {
 	output := &ElevationOutput{}
-	if err := json.Unmarshal(resp.Body(), output); err != nil {
-		return nil, fmt.Errorf("error while decoding http elevation json response data: %w", err)
+	if err := client.call(ctx, ActionElevation, input, output); err != nil {
+		return nil, err
 	}
 
 	return output, nil
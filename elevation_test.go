@@ -3,6 +3,7 @@ package client
 import (
 	"testing"
 
+	"github.com/angelodlfrtr/valhalla-http-client-go/polyline"
 	"github.com/gotidy/ptr"
 )
 
@@ -24,3 +25,22 @@ func TestElevation(t *testing.T) {
 
 	t.Log(output)
 }
+
+func TestElevationOutputDecodedPolyline(t *testing.T) {
+	points := []polyline.Point{{Lat: 42.91358, Lon: 0.13727}, {Lat: 42.91361, Lon: 0.13723}}
+	input := &ElevationInput{ShapeFormat: ptr.String("polyline5")}
+	output := &ElevationOutput{EncodedPolyline: ptr.String(polyline.Encode(points, polyline.Precision5))}
+
+	decoded, err := output.DecodedPolyline(input.ShapePrecision())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded) != len(points) || decoded[1] != points[1] {
+		t.Fatalf("expected %v, got %v", points, decoded)
+	}
+
+	if precision := (&ElevationInput{}).ShapePrecision(); precision != polyline.Precision6 {
+		t.Fatalf("expected polyline6 by default, got %d", precision)
+	}
+}
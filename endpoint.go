@@ -0,0 +1,233 @@
+package client
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultEndpointMaxFailures = 3
+	defaultEndpointCooldown    = 30 * time.Second
+)
+
+// ErrNoEndpointAvailable is returned when the client has no endpoint to send a request to
+var ErrNoEndpointAvailable = errors.New("no valhalla endpoint available")
+
+// EndpointConfig is the configuration of one valhalla endpoint of a pool
+type EndpointConfig struct {
+	// URL base url of the valhalla service, ex: https://valhalla.example.com
+	URL string `json:"url" yaml:"url"`
+
+	// Weight relative weight of the endpoint, used by WeightedBalancer. Default 1.
+	Weight int `json:"weight" yaml:"weight"`
+
+	// Throttle (optional) traffic limits of the endpoint, overriding ClientConfig.Throttle.
+	Throttle *ThrottleConfig `json:"throttle" yaml:"throttle"`
+}
+
+// EndpointHealthConfig configures the passive health tracking of endpoints.
+// An endpoint is ejected from the pool after MaxFailures consecutive failures
+// (transport errors or 5xx responses) and re-admitted after Cooldown, with its
+// failures count reset.
+type EndpointHealthConfig struct {
+	// MaxFailures number of consecutive failures before ejecting an endpoint. Default 3.
+	MaxFailures int `json:"max_failures" yaml:"max_failures"`
+
+	// Cooldown duration an endpoint stays ejected. Default 30s.
+	Cooldown time.Duration `json:"cooldown" yaml:"cooldown"`
+}
+
+// Endpoint is a valhalla endpoint of the client pool, with its runtime state
+type Endpoint struct {
+	url    string
+	weight int
+
+	inFlight int64
+	throttle *endpointThrottle
+	breaker  *circuitBreaker
+
+	mu           sync.Mutex
+	failures     int
+	ejectedUntil time.Time
+}
+
+// URL returns the base url of the endpoint
+func (endpoint *Endpoint) URL() string {
+	return endpoint.url
+}
+
+// Weight returns the weight of the endpoint
+func (endpoint *Endpoint) Weight() int {
+	return endpoint.weight
+}
+
+// InFlight returns the number of requests currently sent to the endpoint
+func (endpoint *Endpoint) InFlight() int64 {
+	return atomic.LoadInt64(&endpoint.inFlight)
+}
+
+// Healthy returns false if the endpoint is currently ejected from the pool
+func (endpoint *Endpoint) Healthy() bool {
+	endpoint.mu.Lock()
+	defer endpoint.mu.Unlock()
+
+	return !time.Now().Before(endpoint.ejectedUntil)
+}
+
+// CircuitState returns the state of the circuit breaker of the endpoint,
+// always closed if no circuit breaker is configured
+func (endpoint *Endpoint) CircuitState() CircuitState {
+	if endpoint.breaker == nil {
+		return CircuitClosed
+	}
+
+	return endpoint.breaker.current()
+}
+
+// succeeded resets the consecutive failures of the endpoint
+func (endpoint *Endpoint) succeeded() {
+	endpoint.mu.Lock()
+	defer endpoint.mu.Unlock()
+
+	endpoint.failures = 0
+}
+
+// failed records a failure, ejecting the endpoint when it reaches cfg max failures.
+// Failures before an expired ejection are not counted.
+func (endpoint *Endpoint) failed(cfg *EndpointHealthConfig) {
+	maxFailures := defaultEndpointMaxFailures
+	cooldown := defaultEndpointCooldown
+
+	if cfg != nil {
+		if cfg.MaxFailures > 0 {
+			maxFailures = cfg.MaxFailures
+		}
+
+		if cfg.Cooldown > 0 {
+			cooldown = cfg.Cooldown
+		}
+	}
+
+	endpoint.mu.Lock()
+	defer endpoint.mu.Unlock()
+
+	now := time.Now()
+	if !endpoint.ejectedUntil.IsZero() && !now.Before(endpoint.ejectedUntil) {
+		endpoint.failures = 0
+		endpoint.ejectedUntil = time.Time{}
+	}
+
+	endpoint.failures++
+	if endpoint.failures >= maxFailures {
+		endpoint.ejectedUntil = now.Add(cooldown)
+	}
+}
+
+// endpointPool is the set of endpoints requests are balanced on
+type endpointPool struct {
+	endpoints []*Endpoint
+	balancer  Balancer
+	health    *EndpointHealthConfig
+}
+
+// newEndpointPool creates the pool of endpoints from given client config
+func newEndpointPool(cfg *ClientConfig) *endpointPool {
+	pool := &endpointPool{
+		balancer: cfg.Balancer,
+		health:   cfg.EndpointHealth,
+	}
+
+	if pool.balancer == nil {
+		pool.balancer = &RoundRobinBalancer{}
+	}
+
+	endpointsCfg := cfg.Endpoints
+	if len(endpointsCfg) == 0 {
+		endpointsCfg = []*EndpointConfig{{URL: cfg.Endpoint}}
+	}
+
+	for _, endpointCfg := range endpointsCfg {
+		weight := endpointCfg.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+
+		throttle := endpointCfg.Throttle
+		if throttle == nil {
+			throttle = cfg.Throttle
+		}
+
+		pool.endpoints = append(pool.endpoints, &Endpoint{
+			url:      endpointCfg.URL,
+			weight:   weight,
+			throttle: newEndpointThrottle(throttle),
+			breaker:  newCircuitBreaker(endpointCfg.URL, cfg.CircuitBreaker),
+		})
+	}
+
+	return pool
+}
+
+// endpointSet endpoints already tried by a request, safe for concurrent use
+type endpointSet struct {
+	mu        sync.Mutex
+	endpoints map[*Endpoint]bool
+}
+
+func newEndpointSet() *endpointSet {
+	return &endpointSet{endpoints: map[*Endpoint]bool{}}
+}
+
+// pick returns an endpoint of pool not in the set and adds it to the set, nil if none
+func (set *endpointSet) pick(pool *endpointPool) *Endpoint {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	endpoint := pool.pick(set.endpoints)
+	if endpoint != nil {
+		set.endpoints[endpoint] = true
+	}
+
+	return endpoint
+}
+
+// remove removes endpoint from the set, allowing to pick it again
+func (set *endpointSet) remove(endpoint *Endpoint) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	delete(set.endpoints, endpoint)
+}
+
+// pick returns an endpoint not in exclude, preferring healthy ones.
+// Ejected endpoints are used only if all others are ejected, endpoints whose
+// circuit breaker rejects requests are never used.
+// Returns nil if all endpoints are excluded or rejecting requests.
+func (pool *endpointPool) pick(exclude map[*Endpoint]bool) *Endpoint {
+	healthy := make([]*Endpoint, 0, len(pool.endpoints))
+	ejected := make([]*Endpoint, 0)
+
+	for _, endpoint := range pool.endpoints {
+		if exclude[endpoint] || (endpoint.breaker != nil && !endpoint.breaker.ready()) {
+			continue
+		}
+
+		if endpoint.Healthy() {
+			healthy = append(healthy, endpoint)
+		} else {
+			ejected = append(ejected, endpoint)
+		}
+	}
+
+	if len(healthy) > 0 {
+		return pool.balancer.Pick(healthy)
+	}
+
+	if len(ejected) > 0 {
+		return pool.balancer.Pick(ejected)
+	}
+
+	return nil
+}
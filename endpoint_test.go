@@ -0,0 +1,113 @@
+package client
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestEndpointPoolFailover(t *testing.T) {
+	calls := int32(0)
+	cfg := &ClientConfig{
+		Endpoints: []*EndpointConfig{
+			{URL: "http://down.local"},
+			{URL: "http://up.local"},
+		},
+		EndpointHealth: &EndpointHealthConfig{MaxFailures: 2, Cooldown: 50 * time.Millisecond},
+	}
+
+	clt := getLocalTestPoolClient(t, cfg, map[string]fasthttp.RequestHandler{
+		"up.local": func(ctx *fasthttp.RequestCtx) {
+			atomic.AddInt32(&calls, 1)
+			ctx.SetBodyString(`{"trip": {}}`)
+		},
+	})
+
+	for i := 0; i < 4; i++ {
+		if _, err := clt.Route(&RouteInput{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if atomic.LoadInt32(&calls) != 4 {
+		t.Fatalf("expected 4 calls to up endpoint, got %d", calls)
+	}
+
+	down := clt.Endpoints()[0]
+	if down.Healthy() {
+		t.Fatal("expected down endpoint to be ejected")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if !down.Healthy() {
+		t.Fatal("expected down endpoint to be re-admitted after cooldown")
+	}
+}
+
+func TestEndpointFailuresResetAfterCooldown(t *testing.T) {
+	cfg := &EndpointHealthConfig{MaxFailures: 2, Cooldown: 20 * time.Millisecond}
+	endpoint := &Endpoint{}
+
+	endpoint.failed(cfg)
+	endpoint.failed(cfg)
+
+	if endpoint.Healthy() {
+		t.Fatal("expected endpoint to be ejected")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	endpoint.failed(cfg)
+	if !endpoint.Healthy() {
+		t.Fatal("expected one failure after cooldown not to eject the endpoint again")
+	}
+
+	endpoint.failed(cfg)
+	if endpoint.Healthy() {
+		t.Fatal("expected endpoint to be ejected after max failures")
+	}
+}
+
+func TestEndpointPoolAllDown(t *testing.T) {
+	cfg := &ClientConfig{
+		Endpoints: []*EndpointConfig{
+			{URL: "http://down1.local"},
+			{URL: "http://down2.local"},
+		},
+	}
+
+	clt := getLocalTestPoolClient(t, cfg, nil)
+
+	if _, err := clt.Route(&RouteInput{}); err == nil {
+		t.Fatal("expected error when all endpoints are down")
+	}
+}
+
+func TestBalancers(t *testing.T) {
+	endpoints := []*Endpoint{
+		{url: "a", weight: 1},
+		{url: "b", weight: 3},
+	}
+
+	rr := &RoundRobinBalancer{}
+	if rr.Pick(endpoints) != endpoints[0] || rr.Pick(endpoints) != endpoints[1] || rr.Pick(endpoints) != endpoints[0] {
+		t.Fatal("expected round robin balancer to pick endpoints in turn")
+	}
+
+	endpoints[0].inFlight = 2
+	if (&LeastInFlightBalancer{}).Pick(endpoints) != endpoints[1] {
+		t.Fatal("expected least in flight balancer to pick endpoint b")
+	}
+
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		counts[(&WeightedBalancer{}).Pick(endpoints).URL()]++
+	}
+
+	if counts["b"] < 2*counts["a"] {
+		t.Fatalf("expected weighted balancer to favor endpoint b, got %v", counts)
+	}
+}
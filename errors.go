@@ -1,14 +1,348 @@
 package client
 
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/valyala/fasthttp"
+)
+
+// ErrorCode is a valhalla error code, as returned in the error_code field of error responses.
+// See https://valhalla.github.io/valhalla/api/turn-by-turn/api-reference/#http-status-codes-and-conditions
+//
+// ErrorCode implements error so known codes can be matched with errors.Is:
+//
+//	if errors.Is(err, client.ErrNoPathFound) { ... }
+type ErrorCode int
+
+// Input parsing and validation errors (loki, 1xx)
+const (
+	ErrFailedToParseJSON          ErrorCode = 100
+	ErrActionNotFound             ErrorCode = 106
+	ErrNotImplemented             ErrorCode = 107
+	ErrMissingLocations           ErrorCode = 110
+	ErrMissingTime                ErrorCode = 111
+	ErrMissingSourcesTargets      ErrorCode = 112
+	ErrMissingContours            ErrorCode = 113
+	ErrMissingShape               ErrorCode = 114
+	ErrInsufficientLocations      ErrorCode = 120
+	ErrInsufficientSources        ErrorCode = 121
+	ErrInsufficientTargets        ErrorCode = 122
+	ErrInsufficientShape          ErrorCode = 123
+	ErrNoCostingProvided          ErrorCode = 124
+	ErrNoCostingFound             ErrorCode = 125
+	ErrNoShapeProvided            ErrorCode = 126
+	ErrUnknownDateTimeType        ErrorCode = 128
+	ErrInvalidDateTime            ErrorCode = 129
+	ErrFailedToParseLocation      ErrorCode = 130
+	ErrFailedToParseSource        ErrorCode = 131
+	ErrFailedToParseTarget        ErrorCode = 132
+	ErrFailedToParseAvoid         ErrorCode = 133
+	ErrFailedToParseShape         ErrorCode = 134
+	ErrFailedToParseTrace         ErrorCode = 135
+	ErrDurationsMismatch          ErrorCode = 136
+	ErrFailedToParsePolygon       ErrorCode = 137
+	ErrMultimodalNotSupported     ErrorCode = 140
+	ErrMultimodalArriveBy         ErrorCode = 141
+	ErrIsochroneArriveBy          ErrorCode = 142
+	ErrClosuresConflict           ErrorCode = 143
+	ErrMaxLocationsExceeded       ErrorCode = 150
+	ErrMaxTimeExceeded            ErrorCode = 151
+	ErrMaxContoursExceeded        ErrorCode = 152
+	ErrTooManyShapePoints         ErrorCode = 153
+	ErrMaxPathDistanceExceeded    ErrorCode = 154
+	ErrTransitStartEndDistance    ErrorCode = 155
+	ErrTransitTransferDistance    ErrorCode = 156
+	ErrMaxAvoidLocationsExceeded  ErrorCode = 157
+	ErrTraceOptionOutOfBounds     ErrorCode = 158
+	ErrNoTimestamps               ErrorCode = 159
+	ErrDepartAtDateTimeRequired   ErrorCode = 160
+	ErrArriveByDateTimeRequired   ErrorCode = 161
+	ErrDateTimeFormat             ErrorCode = 162
+	ErrInvalidDateType            ErrorCode = 163
+	ErrInvalidShapeFormat         ErrorCode = 164
+	ErrInvariantDateTimeRequired  ErrorCode = 165
+	ErrMaxDistanceExceeded        ErrorCode = 166
+	ErrMaxPolygonsPerimeterExceed ErrorCode = 167
+	ErrUnconnectedRegions         ErrorCode = 170
+	ErrNoSuitableEdges            ErrorCode = 171
+	ErrBreakageDistanceExceeded   ErrorCode = 172
+	ErrUnknownInput               ErrorCode = 199
+)
+
+// Directions errors (odin, 2xx)
+const (
+	ErrDirectionsParse   ErrorCode = 200
+	ErrDirectionsBuild   ErrorCode = 202
+	ErrUnknownDirections ErrorCode = 299
+)
+
+// Elevation errors (skadi, 3xx)
+const (
+	ErrElevationParse             ErrorCode = 300
+	ErrElevationNoShape           ErrorCode = 310
+	ErrElevationInsufficientShape ErrorCode = 311
+	ErrElevationMissingShape      ErrorCode = 312
+	ErrElevationResampleDistance  ErrorCode = 313
+	ErrElevationTooManyPoints     ErrorCode = 314
+	ErrUnknownElevation           ErrorCode = 399
+)
+
+// Path finding errors (thor, 4xx)
+const (
+	ErrUnknownAction         ErrorCode = 400
+	ErrMatrixMaxIterations   ErrorCode = 430
+	ErrTransitStopTooFar     ErrorCode = 440
+	ErrLocationUnreachable   ErrorCode = 441
+	ErrNoPathFound           ErrorCode = 442
+	ErrExactRouteMatchFailed ErrorCode = 443
+	ErrMapMatchFailed        ErrorCode = 444
+	ErrInvalidShapeMatch     ErrorCode = 445
+	ErrUnknownPathFinding    ErrorCode = 499
+)
+
+// Serialization errors (tyr, 5xx)
+const (
+	ErrSerializationParse ErrorCode = 500
+	ErrUnknownSerializer  ErrorCode = 599
+)
+
+var errorCodesMessages = map[ErrorCode]string{
+	ErrFailedToParseJSON:          "failed to parse json request",
+	ErrActionNotFound:             "action not found",
+	ErrNotImplemented:             "not implemented",
+	ErrMissingLocations:           "insufficiently specified required parameter 'locations'",
+	ErrMissingTime:                "insufficiently specified required parameter 'time'",
+	ErrMissingSourcesTargets:      "insufficiently specified required parameter 'locations' or 'sources & targets'",
+	ErrMissingContours:            "insufficiently specified required parameter 'contours'",
+	ErrMissingShape:               "insufficiently specified required parameter 'shape' or 'encoded_polyline'",
+	ErrInsufficientLocations:      "insufficient number of locations provided",
+	ErrInsufficientSources:        "insufficient number of sources provided",
+	ErrInsufficientTargets:        "insufficient number of targets provided",
+	ErrInsufficientShape:          "insufficient shape provided",
+	ErrNoCostingProvided:          "no edge/node costing provided",
+	ErrNoCostingFound:             "no costing method found",
+	ErrNoShapeProvided:            "no shape provided",
+	ErrUnknownDateTimeType:        "unknown date_time type",
+	ErrInvalidDateTime:            "invalid date_time provided",
+	ErrFailedToParseLocation:      "failed to parse location",
+	ErrFailedToParseSource:        "failed to parse source",
+	ErrFailedToParseTarget:        "failed to parse target",
+	ErrFailedToParseAvoid:         "failed to parse avoid",
+	ErrFailedToParseShape:         "failed to parse shape",
+	ErrFailedToParseTrace:         "failed to parse trace",
+	ErrDurationsMismatch:          "durations size not compatible with trace size",
+	ErrFailedToParsePolygon:       "failed to parse polygon",
+	ErrMultimodalNotSupported:     "action does not support multimodal costing",
+	ErrMultimodalArriveBy:         "arrive by for multimodal not implemented yet",
+	ErrIsochroneArriveBy:          "arrive by not implemented for isochrones",
+	ErrClosuresConflict:           "ignore_closures in costing and exclude_closures in search_filter cannot both be specified",
+	ErrMaxLocationsExceeded:       "exceeded max locations",
+	ErrMaxTimeExceeded:            "exceeded max time",
+	ErrMaxContoursExceeded:        "exceeded max contours",
+	ErrTooManyShapePoints:         "too many shape points",
+	ErrMaxPathDistanceExceeded:    "path distance exceeds the max distance limit",
+	ErrTransitStartEndDistance:    "outside the valid walking distance at the beginning or end of a multimodal route",
+	ErrTransitTransferDistance:    "outside the valid walking distance between stops of a multimodal route",
+	ErrMaxAvoidLocationsExceeded:  "exceeded max avoid locations",
+	ErrTraceOptionOutOfBounds:     "input trace option is out of bounds",
+	ErrNoTimestamps:               "use_timestamps set with no timestamps present",
+	ErrDepartAtDateTimeRequired:   "date and time required for origin for date_type of depart at",
+	ErrArriveByDateTimeRequired:   "date and time required for destination for date_type of arrive by",
+	ErrDateTimeFormat:             "date and time is invalid, format is YYYY-MM-DDTHH:MM",
+	ErrInvalidDateType:            "invalid date_type",
+	ErrInvalidShapeFormat:         "invalid shape format",
+	ErrInvariantDateTimeRequired:  "date and time required for destination for date_type of invariant",
+	ErrMaxDistanceExceeded:        "exceeded max distance",
+	ErrMaxPolygonsPerimeterExceed: "exceeded maximum circumference for exclude_polygons",
+	ErrUnconnectedRegions:         "locations are in unconnected regions",
+	ErrNoSuitableEdges:            "no suitable edges near location",
+	ErrBreakageDistanceExceeded:   "exceeded breakage distance for all pairs",
+	ErrDirectionsParse:            "failed to parse intermediate request format",
+	ErrDirectionsBuild:            "could not build directions for trip leg",
+	ErrElevationParse:             "failed to parse intermediate request format",
+	ErrElevationNoShape:           "no shape provided",
+	ErrElevationInsufficientShape: "insufficient shape provided",
+	ErrElevationMissingShape:      "insufficiently specified required parameter 'shape' or 'encoded_polyline'",
+	ErrElevationResampleDistance:  "resample_distance is too small",
+	ErrElevationTooManyPoints:     "too many shape points",
+	ErrUnknownAction:              "unknown action",
+	ErrMatrixMaxIterations:        "exceeded max iterations in matrix computation",
+	ErrTransitStopTooFar:          "cannot reach destination, too far from a transit stop",
+	ErrLocationUnreachable:        "location is unreachable",
+	ErrNoPathFound:                "no path could be found for input",
+	ErrExactRouteMatchFailed:      "exact route match algorithm failed to find path",
+	ErrMapMatchFailed:             "map match algorithm failed to find path",
+	ErrInvalidShapeMatch:          "invalid shape_match algorithm",
+	ErrSerializationParse:         "failed to parse intermediate request format",
+}
+
+// Error returns the documented message of the code
+func (code ErrorCode) Error() string {
+	if msg, ok := errorCodesMessages[code]; ok {
+		return fmt.Sprintf("valhalla error %d: %s", int(code), msg)
+	}
+
+	return fmt.Sprintf("valhalla error %d", int(code))
+}
+
+// Error classes, matching ranges of error codes. Use with errors.Is:
+//
+//	if errors.Is(err, client.ErrClassLimit) { ... }
+var (
+	// ErrClassInput input parsing and validation errors (100 to 149)
+	ErrClassInput = errors.New("valhalla invalid input")
+
+	// ErrClassLimit service limits exceeded (150 to 169)
+	ErrClassLimit = errors.New("valhalla service limit exceeded")
+
+	// ErrClassLocation locations not correlated to the route network (170 to 199)
+	ErrClassLocation = errors.New("valhalla location error")
+
+	// ErrClassDirections directions building errors (2xx)
+	ErrClassDirections = errors.New("valhalla directions error")
+
+	// ErrClassElevation elevation errors (3xx)
+	ErrClassElevation = errors.New("valhalla elevation error")
+
+	// ErrClassPathFinding path finding errors, ie: no path found (4xx)
+	ErrClassPathFinding = errors.New("valhalla path finding error")
+
+	// ErrClassSerialization response serialization errors (5xx)
+	ErrClassSerialization = errors.New("valhalla serialization error")
+)
+
+// Class returns the error class of the code, nil for unknown ranges
+func (code ErrorCode) Class() error {
+	switch {
+	case code >= 100 && code < 150:
+		return ErrClassInput
+	case code >= 150 && code < 170:
+		return ErrClassLimit
+	case code >= 170 && code < 200:
+		return ErrClassLocation
+	case code >= 200 && code < 300:
+		return ErrClassDirections
+	case code >= 300 && code < 400:
+		return ErrClassElevation
+	case code >= 400 && code < 500:
+		return ErrClassPathFinding
+	case code >= 500 && code < 600:
+		return ErrClassSerialization
+	}
+
+	return nil
+}
+
 // ErrorResponse from the valhalla server
 type ErrorResponse struct {
-	ErrorCode    string `json:"error_code"`
-	ErrorMessage string `json:"error"`
-	StatusCode   int    `json:"status_code"`
-	Status       string `json:"status"`
+	ErrorCode    ErrorCode `json:"error_code"`
+	ErrorMessage string    `json:"error"`
+	StatusCode   int       `json:"status_code"`
+	Status       string    `json:"status"`
+
+	// OSRMCode code of the errors of requests in the OSRM format, ex: NoRoute.
+	// ErrorCode is not set for these errors.
+	OSRMCode string `json:"-"`
+
+	// Action the valhalla action which returned the error, ex: ActionRoute.
+	Action string `json:"-"`
+
+	// Endpoint the base url of the endpoint which returned the error.
+	Endpoint string `json:"-"`
+
+	// RetryAfter delay requested by the Retry-After header of the response, 0 if none.
+	RetryAfter time.Duration `json:"-"`
 }
 
 // Error as string
 func (err *ErrorResponse) Error() string {
-	return err.Status + ": " + err.ErrorMessage
+	msg := err.Status + ": " + err.ErrorMessage
+	if err.ErrorCode != 0 {
+		msg += fmt.Sprintf(" (error code %d)", int(err.ErrorCode))
+	}
+
+	if err.OSRMCode != "" {
+		msg += " (" + err.OSRMCode + ")"
+	}
+
+	if err.Action != "" {
+		msg = err.Action + ": " + msg
+	}
+
+	return msg
+}
+
+// Is reports whether target is the error code or the error class of the response
+func (err *ErrorResponse) Is(target error) bool {
+	if code, ok := target.(ErrorCode); ok {
+		return err.ErrorCode != 0 && code == err.ErrorCode
+	}
+
+	return target != nil && target == err.ErrorCode.Class()
+}
+
+// Retryable returns true if the error is transient and the request may succeed if retried,
+// ie: the server is overloaded or temporarily unavailable
+func (err *ErrorResponse) Retryable() bool {
+	for _, code := range DefaultRetryableStatusCodes {
+		if code == err.StatusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newErrorResponse decodes the error response of a round trip
+func newErrorResponse(rt *RoundTrip) *ErrorResponse {
+	resp := rt.Response
+	errRes := &ErrorResponse{}
+	if err := json.Unmarshal(resp.Body(), errRes); err != nil {
+		errRes = &ErrorResponse{ErrorMessage: string(resp.Body())}
+	}
+
+	// Requests in the OSRM format fail with an OSRM error body
+	if errRes.ErrorCode == 0 && errRes.ErrorMessage == "" {
+		osrmErr := &osrmErrorResponse{}
+		if err := json.Unmarshal(resp.Body(), osrmErr); err == nil {
+			errRes.OSRMCode, errRes.ErrorMessage = osrmErr.Code, osrmErr.Message
+		}
+	}
+
+	if errRes.StatusCode == 0 {
+		errRes.StatusCode = resp.StatusCode()
+	}
+
+	if errRes.Status == "" {
+		errRes.Status = fasthttp.StatusMessage(resp.StatusCode())
+	}
+
+	errRes.Action = rt.Action
+	errRes.Endpoint = rt.Endpoint
+	errRes.RetryAfter = parseRetryAfter(resp.Header.Peek(fasthttp.HeaderRetryAfter))
+
+	return errRes
+}
+
+// RetryError is returned by the client when a retry policy is configured
+// and the request failed, it wraps the error of the last attempt.
+type RetryError struct {
+	// Attempts number of attempts made before giving up.
+	Attempts int
+
+	// Err error of the last attempt.
+	Err error
+}
+
+// Error as string
+func (err *RetryError) Error() string {
+	return fmt.Sprintf("%s (after %d attempts)", err.Err, err.Attempts)
+}
+
+// Unwrap returns the error of the last attempt
+func (err *RetryError) Unwrap() error {
+	return err.Err
 }
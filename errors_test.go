@@ -0,0 +1,56 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestErrorResponseIs(t *testing.T) {
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetBodyString(`{"error_code": 442, "error": "No path could be found for input", "status_code": 400, "status": "Bad Request"}`)
+	})
+
+	_, err := clt.Route(&RouteInput{})
+
+	if !errors.Is(err, ErrNoPathFound) {
+		t.Fatalf("expected no path found error, got %v", err)
+	}
+
+	if !errors.Is(err, ErrClassPathFinding) || errors.Is(err, ErrClassInput) || errors.Is(err, ErrNoSuitableEdges) {
+		t.Fatalf("unexpected error classification for %v", err)
+	}
+
+	errRes := &ErrorResponse{}
+	if !errors.As(err, &errRes) {
+		t.Fatalf("expected error response, got %v", err)
+	}
+
+	if errRes.Action != ActionRoute || errRes.Endpoint != "http://valhalla.local" || errRes.Retryable() {
+		t.Fatalf("unexpected error response %+v", errRes)
+	}
+}
+
+func TestErrorResponseNotJSON(t *testing.T) {
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+		ctx.SetBodyString("tiles are being rebuilt")
+	})
+
+	_, err := clt.Elevation(&ElevationInput{})
+
+	errRes := &ErrorResponse{}
+	if !errors.As(err, &errRes) {
+		t.Fatalf("expected error response, got %v", err)
+	}
+
+	if errRes.StatusCode != fasthttp.StatusServiceUnavailable || errRes.Status != "Service Unavailable" || !errRes.Retryable() {
+		t.Fatalf("unexpected error response %+v", errRes)
+	}
+
+	if errRes.Error() != "height: Service Unavailable: tiles are being rebuilt" {
+		t.Fatalf("unexpected error message %q", errRes.Error())
+	}
+}
@@ -0,0 +1,223 @@
+package client
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const gpxCreator = "valhalla-http-client-go"
+
+// ErrGPXNoPoints is returned when reading a GPX document without track or route points
+var ErrGPXNoPoints = errors.New("gpx document has no track or route points")
+
+type gpxDocument struct {
+	XMLName xml.Name `xml:"gpx"`
+	XMLNS   string   `xml:"xmlns,attr,omitempty"`
+	Version string   `xml:"version,attr"`
+	Creator string   `xml:"creator,attr"`
+
+	Waypoints []*gpxPoint `xml:"wpt"`
+	Routes    []*gpxRoute `xml:"rte"`
+	Tracks    []*gpxTrack `xml:"trk"`
+}
+
+// gpxCoordinate a latitude or longitude attribute, written in fixed notation
+// as GPX readers reject exponents
+type gpxCoordinate float64
+
+// MarshalXMLAttr implements xml.MarshalerAttr
+func (c gpxCoordinate) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: formatCoordinate(float64(c))}, nil
+}
+
+type gpxPoint struct {
+	Lat  gpxCoordinate `xml:"lat,attr"`
+	Lon  gpxCoordinate `xml:"lon,attr"`
+	Ele  *float64      `xml:"ele,omitempty"`
+	Time string        `xml:"time,omitempty"`
+	Name string        `xml:"name,omitempty"`
+	Desc string        `xml:"desc,omitempty"`
+}
+
+type gpxRoute struct {
+	Name   string      `xml:"name,omitempty"`
+	Points []*gpxPoint `xml:"rtept"`
+}
+
+type gpxTrackSegment struct {
+	Points []*gpxPoint `xml:"trkpt"`
+}
+
+type gpxTrack struct {
+	Name     string             `xml:"name,omitempty"`
+	Segments []*gpxTrackSegment `xml:"trkseg"`
+}
+
+// WriteGPX writes the route as a GPX 1.1 document to w.
+// The document contains one track segment per leg (from the decoded leg shape),
+// one waypoint per trip location and one route point per maneuver,
+// with the maneuver instruction as description.
+func (output *RouteOutput) WriteGPX(w io.Writer) error {
+	doc := &gpxDocument{
+		XMLNS:   "http://www.topografix.com/GPX/1/1",
+		Version: "1.1",
+		Creator: gpxCreator,
+	}
+
+	if output.Trip == nil {
+		return writeXML(w, doc)
+	}
+
+	name := ""
+	if output.ID != nil {
+		name = *output.ID
+	}
+
+	for _, location := range output.Trip.Locations {
+		if location == nil || location.Lat == nil || location.Lon == nil {
+			continue
+		}
+
+		wpt := &gpxPoint{Lat: gpxCoordinate(*location.Lat), Lon: gpxCoordinate(*location.Lon)}
+		if location.Name != nil {
+			wpt.Name = *location.Name
+		}
+
+		doc.Waypoints = append(doc.Waypoints, wpt)
+	}
+
+	rte := &gpxRoute{Name: name}
+	trk := &gpxTrack{Name: name}
+
+	for legIndex, leg := range output.Trip.Legs {
+		shape, err := leg.DecodedShape()
+		if err != nil {
+			return fmt.Errorf("unable to decode shape of leg %d: %w", legIndex, err)
+		}
+
+		seg := &gpxTrackSegment{}
+		for _, point := range shape {
+			seg.Points = append(seg.Points, &gpxPoint{Lat: gpxCoordinate(point.Lat), Lon: gpxCoordinate(point.Lon)})
+		}
+
+		trk.Segments = append(trk.Segments, seg)
+
+		for _, maneuver := range leg.Maneuvers {
+			if maneuver == nil || maneuver.BeginShapeIndex == nil {
+				continue
+			}
+
+			index := *maneuver.BeginShapeIndex
+			if index < 0 || index >= len(shape) {
+				continue
+			}
+
+			rtept := &gpxPoint{
+				Lat:  gpxCoordinate(shape[index].Lat),
+				Lon:  gpxCoordinate(shape[index].Lon),
+				Name: strings.Join(maneuver.StreetNames, ", "),
+			}
+
+			if maneuver.Instruction != nil {
+				rtept.Desc = *maneuver.Instruction
+			}
+
+			rte.Points = append(rte.Points, rtept)
+		}
+	}
+
+	doc.Routes = append(doc.Routes, rte)
+	doc.Tracks = append(doc.Tracks, trk)
+
+	return writeXML(w, doc)
+}
+
+// ReadGPXTrace reads the points of a GPX document from r. Points of all track segments
+// are returned in order, or route points if the document has no track.
+// Point times are set when present in the document.
+func ReadGPXTrace(r io.Reader) ([]*TracePoint, error) {
+	doc := &gpxDocument{}
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, fmt.Errorf("unable to decode gpx document: %w", err)
+	}
+
+	points := []*gpxPoint{}
+	for _, trk := range doc.Tracks {
+		for _, seg := range trk.Segments {
+			points = append(points, seg.Points...)
+		}
+	}
+
+	if len(points) == 0 {
+		for _, rte := range doc.Routes {
+			points = append(points, rte.Points...)
+		}
+	}
+
+	if len(points) == 0 {
+		return nil, ErrGPXNoPoints
+	}
+
+	trace := make([]*TracePoint, 0, len(points))
+	for _, point := range points {
+		lat, lon := float64(point.Lat), float64(point.Lon)
+		tracePoint := &TracePoint{Lat: &lat, Lon: &lon}
+
+		if point.Time != "" {
+			t, err := time.Parse(time.RFC3339, strings.TrimSpace(point.Time))
+			if err != nil {
+				return nil, fmt.Errorf("invalid gpx point time %q: %w", point.Time, err)
+			}
+
+			unix := t.Unix()
+			tracePoint.Time = &unix
+		}
+
+		trace = append(trace, tracePoint)
+	}
+
+	return trace, nil
+}
+
+// NewTraceInputFromGPX returns a map matching input with the shape read from the GPX document r.
+// Timestamps are used if all points have a time.
+func NewTraceInputFromGPX(r io.Reader) (*TraceInput, error) {
+	shape, err := ReadGPXTrace(r)
+	if err != nil {
+		return nil, err
+	}
+
+	useTimestamps := true
+	for _, point := range shape {
+		if point.Time == nil {
+			useTimestamps = false
+			break
+		}
+	}
+
+	input := &TraceInput{Shape: shape}
+	if useTimestamps {
+		input.UseTimestamps = &useTimestamps
+	}
+
+	return input, nil
+}
+
+// NewElevationInputFromGPX returns an elevation input with the shape read from the GPX document r
+func NewElevationInputFromGPX(r io.Reader) (*ElevationInput, error) {
+	trace, err := ReadGPXTrace(r)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &ElevationInput{Shape: make([]*ElevationPoint, 0, len(trace))}
+	for _, point := range trace {
+		input.Shape = append(input.Shape, &ElevationPoint{Lat: *point.Lat, Lon: *point.Lon})
+	}
+
+	return input, nil
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRouteOutputWriteGPX(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := getTestRouteOutput().WriteGPX(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := buf.String()
+	for _, expected := range []string{
+		`<gpx xmlns="http://www.topografix.com/GPX/1/1" version="1.1"`,
+		`<wpt lat="48.390394" lon="-4.486076">`,
+		`<desc>Drive east.</desc>`,
+		`<trkpt lat="48.45252" lon="-4.25252">`,
+	} {
+		if !strings.Contains(doc, expected) {
+			t.Fatalf("expected gpx document to contain %q, got:\n%s", expected, doc)
+		}
+	}
+
+	// Exported document can be read back as a trace
+	trace, err := ReadGPXTrace(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(trace) != 3 || *trace[2].Lat != 48.45252 {
+		t.Fatalf("unexpected trace read from exported gpx: %v", trace)
+	}
+}
+
+func TestRouteOutputWriteGPXFixedNotation(t *testing.T) {
+	lat, lon := 51.4779, -0.000001
+	output := &RouteOutput{Trip: &RouteOutputTrip{Locations: []*RouteLocation{{Lat: &lat, Lon: &lon}}}}
+
+	buf := &bytes.Buffer{}
+	if err := output.WriteGPX(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), `<wpt lat="51.4779" lon="-0.000001">`) {
+		t.Fatalf("expected coordinates in fixed notation, got:\n%s", buf.String())
+	}
+}
+
+func TestNewTraceInputFromGPX(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <trk><trkseg>
+    <trkpt lat="48.390394" lon="-4.486076"><ele>42</ele><time>2022-08-08T23:06:40Z</time></trkpt>
+    <trkpt lat="48.390794" lon="-4.485316"><time>2022-08-08T23:06:50Z</time></trkpt>
+  </trkseg></trk>
+</gpx>`
+
+	input, err := NewTraceInputFromGPX(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(input.Shape) != 2 || input.UseTimestamps == nil || !*input.UseTimestamps {
+		t.Fatalf("unexpected trace input %+v", input)
+	}
+
+	if *input.Shape[1].Time != 1660000010 {
+		t.Fatalf("unexpected point time %d", *input.Shape[1].Time)
+	}
+
+	elevationInput, err := NewElevationInputFromGPX(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(elevationInput.Shape) != 2 || elevationInput.Shape[0].Lat != 48.390394 {
+		t.Fatalf("unexpected elevation input %+v", elevationInput)
+	}
+
+	if _, err := ReadGPXTrace(strings.NewReader(`<gpx version="1.1"></gpx>`)); err != ErrGPXNoPoints {
+		t.Fatalf("expected no points error, got %v", err)
+	}
+}
+
+func TestRouteOutputWriteKML(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := getTestRouteOutput().WriteKML(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := buf.String()
+	for _, expected := range []string{
+		`<kml xmlns="http://www.opengis.net/kml/2.2">`,
+		`<coordinates>-4.486076,48.390394 -4.39826,48.40912 -4.25252,48.45252</coordinates>`,
+		`<description>You have arrived at your destination.</description>`,
+	} {
+		if !strings.Contains(doc, expected) {
+			t.Fatalf("expected kml document to contain %q, got:\n%s", expected, doc)
+		}
+	}
+}
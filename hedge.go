@@ -0,0 +1,320 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHedgeMinSamples  = 20
+	defaultHedgeMaxHedges   = 1
+	defaultHedgeBudgetRatio = 0.1
+	hedgeBudgetBurst        = 10
+	hedgeLatencySamples     = 256
+)
+
+// HedgingPolicy configures hedged requests: when the response of a request has not
+// arrived after a delay, a duplicate is sent to another endpoint of the pool and the
+// first response is used, the other request being cancelled.
+type HedgingPolicy struct {
+	// Delay before sending a hedged request. Used until enough latencies are observed
+	// when Percentile is set. Requests are not hedged if 0 and Percentile is not usable.
+	Delay time.Duration `json:"delay" yaml:"delay"`
+
+	// Percentile (optional) percentile of the observed latencies used as delay, from 0 to 1
+	// (ie: 0.95). Latencies are observed per action.
+	Percentile float64 `json:"percentile" yaml:"percentile"`
+
+	// MinSamples number of latencies observed before Percentile is used. Default 20.
+	MinSamples int `json:"min_samples" yaml:"min_samples"`
+
+	// MaxHedges maximum number of hedged requests per request, sent every delay. Default 1.
+	MaxHedges int `json:"max_hedges" yaml:"max_hedges"`
+
+	// BudgetRatio maximum ratio of hedged requests to requests, capping the extra load
+	// (ie: 0.1 for at most 10% more requests). Default 0.1.
+	BudgetRatio float64 `json:"budget_ratio" yaml:"budget_ratio"`
+
+	// Actions (optional) hedged actions. Default to ActionRoute and ActionMatrix.
+	Actions []string `json:"actions" yaml:"actions"`
+}
+
+// hedged returns true if requests of action are hedged
+func (policy *HedgingPolicy) hedged(action string) bool {
+	if policy == nil {
+		return false
+	}
+
+	if len(policy.Actions) == 0 {
+		return action == ActionRoute || action == ActionMatrix
+	}
+
+	return containsString(policy.Actions, action)
+}
+
+// hedger is the runtime state of the hedging policy of a client
+type hedger struct {
+	policy *HedgingPolicy
+
+	minSamples  int
+	maxHedges   int
+	budgetRatio float64
+
+	mu        sync.Mutex
+	budget    float64
+	latencies map[string]*latencySamples
+}
+
+// newHedger returns the hedger of policy, nil if policy is nil
+func newHedger(policy *HedgingPolicy) *hedger {
+	if policy == nil {
+		return nil
+	}
+
+	h := &hedger{
+		policy:      policy,
+		minSamples:  policy.MinSamples,
+		maxHedges:   policy.MaxHedges,
+		budgetRatio: policy.BudgetRatio,
+		latencies:   map[string]*latencySamples{},
+	}
+
+	if h.minSamples <= 0 {
+		h.minSamples = defaultHedgeMinSamples
+	}
+
+	if h.maxHedges <= 0 {
+		h.maxHedges = defaultHedgeMaxHedges
+	}
+
+	if h.budgetRatio <= 0 {
+		h.budgetRatio = defaultHedgeBudgetRatio
+	}
+
+	return h
+}
+
+// delay returns the delay before hedging a request of action, 0 if it must not be hedged
+func (h *hedger) delay(action string) time.Duration {
+	if h.policy.Percentile <= 0 {
+		return h.policy.Delay
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := h.latencies[action]
+	if samples == nil || samples.len() < h.minSamples {
+		return h.policy.Delay
+	}
+
+	return samples.percentile(h.policy.Percentile)
+}
+
+// observe records the latency of a successful request of action
+func (h *hedger) observe(action string, latency time.Duration) {
+	if h.policy.Percentile <= 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := h.latencies[action]
+	if samples == nil {
+		samples = &latencySamples{}
+		h.latencies[action] = samples
+	}
+
+	samples.add(latency)
+}
+
+// earn adds the budget of a request
+func (h *hedger) earn() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.budget += h.budgetRatio
+	if h.budget > hedgeBudgetBurst {
+		h.budget = hedgeBudgetBurst
+	}
+}
+
+// spend takes the budget of a hedged request, returns false if the budget is exhausted
+func (h *hedger) spend() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.budget < 1 {
+		return false
+	}
+
+	h.budget--
+
+	return true
+}
+
+// latencySamples the last observed latencies
+type latencySamples struct {
+	values []time.Duration
+	next   int
+}
+
+func (samples *latencySamples) len() int {
+	return len(samples.values)
+}
+
+func (samples *latencySamples) add(latency time.Duration) {
+	if len(samples.values) < hedgeLatencySamples {
+		samples.values = append(samples.values, latency)
+		return
+	}
+
+	samples.values[samples.next] = latency
+	samples.next = (samples.next + 1) % hedgeLatencySamples
+}
+
+// percentile returns the p percentile of the samples (nearest rank)
+func (samples *latencySamples) percentile(p float64) time.Duration {
+	sorted := make([]time.Duration, len(samples.values))
+	copy(sorted, samples.values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+
+	return sorted[rank]
+}
+
+// hedgeResult result of one of the requests of a hedged attempt
+type hedgeResult struct {
+	raw       *rawOutput
+	transport bool
+	err       error
+}
+
+// hedgedAttempt is attempt sending hedged requests to other endpoints according to
+// the client hedging policy. The first response is decoded into output, the other
+// requests are cancelled. Failing over on transport errors, a request never uses
+// an endpoint already used by another request of the attempt.
+func (client *Client) hedgedAttempt(
+	ctx context.Context,
+	action string,
+	attempt int,
+	body []byte,
+	output interface{},
+) (bool, error) {
+	h := client.hedger
+	h.earn()
+
+	tried := newEndpointSet()
+
+	first := tried.pick(client.endpoints)
+	if first == nil {
+		return client.attempt(ctx, tried, action, attempt, body, output)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgeResult, 1+h.maxHedges)
+	launch := func(endpoint *Endpoint) {
+		go func() {
+			start := time.Now()
+			raw := &rawOutput{}
+
+			transport, err := client.attemptFrom(ctx, tried, endpoint, action, attempt, body, raw)
+			if err == nil {
+				h.observe(action, time.Since(start))
+			}
+
+			results <- hedgeResult{raw: raw, transport: transport, err: err}
+		}()
+	}
+
+	launch(first)
+	pending, hedges := 1, 0
+
+	// A hedge is sent every delay while budget and endpoints are available,
+	// the schedule is not delayed by failed requests
+	var timer *time.Timer
+	var hedgeTimer <-chan time.Time
+
+	schedule := func() {
+		hedgeTimer = nil
+		if hedges >= h.maxHedges {
+			return
+		}
+
+		if delay := h.delay(action); delay > 0 {
+			timer = time.NewTimer(delay)
+			hedgeTimer = timer.C
+		}
+	}
+
+	schedule()
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-hedgeTimer:
+			hedgeTimer = nil
+			if ctx.Err() != nil {
+				continue
+			}
+
+			endpoint := tried.pick(client.endpoints)
+			if endpoint == nil {
+				continue
+			}
+
+			if !h.spend() {
+				tried.remove(endpoint)
+				continue
+			}
+
+			hedges++
+			pending++
+			launch(endpoint)
+			schedule()
+		case result := <-results:
+			pending--
+
+			// Errors of the endpoint wait for the other requests, error responses
+			// which would be the same on all endpoints (ie: invalid input) are final
+			if result.err != nil && pending > 0 && (result.transport || endpointError(result.err)) {
+				continue
+			}
+
+			if result.err != nil {
+				return result.transport, result.err
+			}
+
+			return false, decodeOutput(action, result.raw.pbf, result.raw.body, output)
+		}
+	}
+}
+
+// endpointError returns true if err is an error response of the endpoint itself,
+// other endpoints may succeed: retryable and server error responses
+func endpointError(err error) bool {
+	errRes := &ErrorResponse{}
+	if !errors.As(err, &errRes) {
+		return false
+	}
+
+	return errRes.Retryable() || errRes.StatusCode >= 500
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// firstBalancer always picks the first endpoint
+type firstBalancer struct{}
+
+func (balancer firstBalancer) Pick(endpoints []*Endpoint) *Endpoint {
+	return endpoints[0]
+}
+
+// getHedgingTestClient returns a client hedging between a slow endpoint a, picked first, and a fast endpoint b
+func getHedgingTestClient(t *testing.T, policy *HedgingPolicy, callsA, callsB *int64) *Client {
+	return getLocalTestPoolClient(
+		t,
+		&ClientConfig{
+			Endpoints: []*EndpointConfig{{URL: "http://a.local"}, {URL: "http://b.local"}},
+			Balancer:  firstBalancer{},
+			Hedging:   policy,
+		},
+		map[string]fasthttp.RequestHandler{
+			"a.local": func(ctx *fasthttp.RequestCtx) {
+				atomic.AddInt64(callsA, 1)
+
+				select {
+				case <-time.After(300 * time.Millisecond):
+				case <-ctx.Done():
+				}
+
+				ctx.SetBodyString(`{"id": "a"}`)
+			},
+			"b.local": func(ctx *fasthttp.RequestCtx) {
+				atomic.AddInt64(callsB, 1)
+				ctx.SetBodyString(`{"id": "b"}`)
+			},
+		},
+	)
+}
+
+func TestHedgedRequests(t *testing.T) {
+	callsA, callsB := int64(0), int64(0)
+	clt := getHedgingTestClient(t, &HedgingPolicy{Delay: 20 * time.Millisecond, BudgetRatio: 1}, &callsA, &callsB)
+
+	start := time.Now()
+
+	output, err := clt.Route(&RouteInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if output.ID == nil || *output.ID != "b" {
+		t.Fatalf("expected response of the hedged request, got %+v", output)
+	}
+
+	if elapsed := time.Since(start); elapsed >= 300*time.Millisecond {
+		t.Fatalf("expected hedged request to cut latency, took %s", elapsed)
+	}
+
+	if atomic.LoadInt64(&callsA) != 1 || atomic.LoadInt64(&callsB) != 1 {
+		t.Fatalf("expected 1 call per endpoint, got %d and %d", atomic.LoadInt64(&callsA), atomic.LoadInt64(&callsB))
+	}
+
+	// The slow request is cancelled: not an endpoint failure
+	for start := time.Now(); clt.Endpoints()[0].InFlight() != 0 && time.Since(start) < 100*time.Millisecond; {
+		time.Sleep(time.Millisecond)
+	}
+
+	if clt.Endpoints()[0].InFlight() != 0 || !clt.Endpoints()[0].Healthy() {
+		t.Fatal("expected slow request to be cancelled without failure")
+	}
+
+	// Actions not hedged
+	start = time.Now()
+	clt.Isochrone(&IsochroneInput{}) //nolint:errcheck
+
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond || atomic.LoadInt64(&callsB) != 1 {
+		t.Fatalf("expected isochrone not to be hedged, took %s", elapsed)
+	}
+}
+
+func TestHedgedRequestsBudget(t *testing.T) {
+	callsA, callsB := int64(0), int64(0)
+	clt := getHedgingTestClient(t, &HedgingPolicy{Delay: 10 * time.Millisecond, BudgetRatio: 0.5}, &callsA, &callsB)
+
+	for i := 0; i < 4; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, err := clt.RouteContext(ctx, &RouteInput{})
+		cancel()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Half a hedge is earned by request
+	if a, b := atomic.LoadInt64(&callsA), atomic.LoadInt64(&callsB); a != 4 || b != 2 {
+		t.Fatalf("expected 2 hedged requests, got %d and %d calls", a, b)
+	}
+}
+
+func TestHedgedRequestsNoOtherEndpoint(t *testing.T) {
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		time.Sleep(30 * time.Millisecond)
+		ctx.SetBodyString(`{}`)
+	})
+	clt.hedger = newHedger(&HedgingPolicy{Delay: 5 * time.Millisecond, BudgetRatio: 1})
+
+	if _, err := clt.Route(&RouteInput{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// No duplicate can be sent: the budget is kept
+	if !clt.hedger.spend() {
+		t.Fatal("expected hedge budget not to be spent without another endpoint")
+	}
+}
+
+func TestHedgedRequestsErrorResponse(t *testing.T) {
+	callsB := int64(0)
+
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{
+			Endpoints: []*EndpointConfig{{URL: "http://a.local"}, {URL: "http://b.local"}},
+			Balancer:  firstBalancer{},
+			Hedging:   &HedgingPolicy{Delay: 50 * time.Millisecond, BudgetRatio: 1},
+		},
+		map[string]fasthttp.RequestHandler{
+			"a.local": func(ctx *fasthttp.RequestCtx) {
+				ctx.SetStatusCode(fasthttp.StatusBadRequest)
+				ctx.SetBodyString(`{"error_code": 442, "error": "No path could be found for input", "status_code": 400, "status": "Bad Request"}`)
+			},
+			"b.local": func(ctx *fasthttp.RequestCtx) {
+				atomic.AddInt64(&callsB, 1)
+				ctx.SetBodyString(`{}`)
+			},
+		},
+	)
+
+	if _, err := clt.Route(&RouteInput{}); !errors.Is(err, ErrNoPathFound) {
+		t.Fatalf("expected error response, got %v", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if atomic.LoadInt64(&callsB) != 0 {
+		t.Fatal("expected error response not to be hedged")
+	}
+}
+
+func TestHedgedRequestsServerErrorResponse(t *testing.T) {
+	callsB := int64(0)
+
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{
+			Endpoints: []*EndpointConfig{{URL: "http://a.local"}, {URL: "http://b.local"}},
+			Balancer:  firstBalancer{},
+			Hedging:   &HedgingPolicy{Delay: 50 * time.Millisecond, BudgetRatio: 1},
+		},
+		map[string]fasthttp.RequestHandler{
+			"a.local": func(ctx *fasthttp.RequestCtx) {
+				time.Sleep(100 * time.Millisecond)
+				ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+				ctx.SetBodyString(`{"error": "worker is rebuilding", "status_code": 503, "status": "Service Unavailable"}`)
+			},
+			"b.local": func(ctx *fasthttp.RequestCtx) {
+				atomic.AddInt64(&callsB, 1)
+				time.Sleep(100 * time.Millisecond)
+				ctx.SetBodyString(`{"trip": {}}`)
+			},
+		},
+	)
+
+	output, err := clt.Route(&RouteInput{})
+	if err != nil {
+		t.Fatalf("expected hedged request to succeed after server error, got %v", err)
+	}
+
+	if output.Trip == nil || atomic.LoadInt64(&callsB) != 1 {
+		t.Fatalf("expected output of the hedged request, got %+v", output)
+	}
+}
+
+func TestHedgingPercentileDelay(t *testing.T) {
+	h := newHedger(&HedgingPolicy{Delay: time.Second, Percentile: 0.9, MinSamples: 10})
+
+	for i := 1; i <= 9; i++ {
+		h.observe(ActionRoute, time.Duration(i)*time.Millisecond)
+	}
+
+	if delay := h.delay(ActionRoute); delay != time.Second {
+		t.Fatalf("expected fallback delay before min samples, got %s", delay)
+	}
+
+	h.observe(ActionRoute, 10*time.Millisecond)
+
+	if delay := h.delay(ActionRoute); delay != 9*time.Millisecond {
+		t.Fatalf("expected 90th percentile delay, got %s", delay)
+	}
+
+	if delay := h.delay(ActionMatrix); delay != time.Second {
+		t.Fatalf("expected latencies to be observed per action, got %s", delay)
+	}
+}
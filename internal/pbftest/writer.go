@@ -0,0 +1,104 @@
+// Package pbftest encodes protocol buffers fields, to build the pbf data of tests
+package pbftest
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/angelodlfrtr/valhalla-http-client-go/pbf"
+)
+
+// AppendKey appends the key of field with given wire type to b
+func AppendKey(b []byte, field int, wireType pbf.WireType) []byte {
+	return appendVarint(b, uint64(field)<<3|uint64(wireType))
+}
+
+// AppendUint64 appends a uint64, uint32 or enum field to b
+func AppendUint64(b []byte, field int, value uint64) []byte {
+	b = AppendKey(b, field, pbf.WireVarint)
+	return appendVarint(b, value)
+}
+
+// AppendBool appends a bool field to b
+func AppendBool(b []byte, field int, value bool) []byte {
+	var v uint64
+	if value {
+		v = 1
+	}
+
+	return AppendUint64(b, field, v)
+}
+
+// AppendDouble appends a double field to b
+func AppendDouble(b []byte, field int, value float64) []byte {
+	b = AppendKey(b, field, pbf.WireFixed64)
+	return appendFixed64(b, math.Float64bits(value))
+}
+
+// AppendFloat appends a float field to b
+func AppendFloat(b []byte, field int, value float32) []byte {
+	b = AppendKey(b, field, pbf.WireFixed32)
+	return appendFixed32(b, math.Float32bits(value))
+}
+
+// AppendBytes appends a bytes or embedded message field to b
+func AppendBytes(b []byte, field int, value []byte) []byte {
+	b = AppendKey(b, field, pbf.WireBytes)
+	b = appendVarint(b, uint64(len(value)))
+
+	return append(b, value...)
+}
+
+// AppendString appends a string field to b
+func AppendString(b []byte, field int, value string) []byte {
+	b = AppendKey(b, field, pbf.WireBytes)
+	b = appendVarint(b, uint64(len(value)))
+
+	return append(b, value...)
+}
+
+// AppendPackedUint32 appends a packed repeated uint32 field to b
+func AppendPackedUint32(b []byte, field int, values []uint32) []byte {
+	packed := make([]byte, 0, len(values)*2)
+	for _, value := range values {
+		packed = appendVarint(packed, uint64(value))
+	}
+
+	return AppendBytes(b, field, packed)
+}
+
+// AppendPackedFloat appends a packed repeated float field to b
+func AppendPackedFloat(b []byte, field int, values []float32) []byte {
+	packed := make([]byte, 0, len(values)*4)
+	for _, value := range values {
+		packed = appendFixed32(packed, math.Float32bits(value))
+	}
+
+	return AppendBytes(b, field, packed)
+}
+
+// appendVarint appends the varint encoding of v to b
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+
+	return append(b, byte(v))
+}
+
+// appendFixed32 appends the little endian encoding of v to b
+func appendFixed32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+
+	return append(b, buf[:]...)
+}
+
+// appendFixed64 appends the little endian encoding of v to b
+func appendFixed64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+
+	return append(b, buf[:]...)
+}
@@ -1,11 +1,9 @@
 package client
 
 import (
-	"fmt"
+	"context"
 
-	"github.com/goccy/go-json"
 	"github.com/paulmach/go.geojson"
-	"github.com/valyala/fasthttp"
 )
 
 type IsochroneInputLocation struct {
@@ -13,24 +11,10 @@ type IsochroneInputLocation struct {
 	Lon *float64 `json:"lon,omitempty"`
 }
 
-type IsochroneInputDateTime struct {
-	// Type of date time.
-	//
-	// 0 - Current departure time.
-	//
-	// 1 - Specified departure time
-	//
-	// 2 - Specified arrival time. Not yet implemented for multimodal costing method.
-	//
-	// 3 - Invariant specified time. Time does not vary over the course of the path.
-	// Not implemented for multimodal or bike share routing
-	Type *int `json:"type,omitempty"`
-
-	// Value the date and time is specified in ISO 8601 format (YYYY-MM-DDThh:mm)
-	// in the local time zone of departure or arrival.
-	// For example "2016-07-03T08:06"
-	Value *string `json:"value,omitempty"`
-}
+// IsochroneInputDateTime date time of isochrone inputs.
+//
+// Deprecated: use DateTime.
+type IsochroneInputDateTime = DateTime
 
 type IsochroneInputContour struct {
 	// Time a floating point value specifying the time in minutes for the contour.
@@ -63,7 +47,8 @@ type IsochroneInput struct {
 	// DateTime 	The local date and time at the location.
 	// These parameters apply only for multimodal requests and are not used with other
 	// costing methods.
-	DateTime *IsochroneInputDateTime `json:"date_time,omitempty"`
+	// See DepartAt, ArriveBy, Now and Invariant.
+	DateTime *DateTime `json:"date_time,omitempty"`
 
 	// ID name of the isochrone request.
 	// If id is specified, the name is returned with the response.
@@ -96,35 +81,15 @@ type IsochroneInput struct {
 
 // Isochrone returns the isochrone for the specified locations.
 func (client *Client) Isochrone(input *IsochroneInput) (*geojson.FeatureCollection, error) {
-	req, err := client.buildBaseRequest(fasthttp.MethodPost, "/route", input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build request for route: %w", err)
-	}
-	defer fasthttp.ReleaseRequest(req)
-
-	// Acquire response
-	resp := fasthttp.AcquireResponse()
-	defer fasthttp.ReleaseResponse(resp)
-
-	if err := client.httpClient.Do(req, resp); err != nil {
-		return nil, fmt.Errorf("error while calling http route service: %w", err)
-	}
-
-	if resp.StatusCode() != fasthttp.StatusOK {
-		errRes := &ErrorResponse{}
-		if err := json.Unmarshal(resp.Body(), errRes); err != nil {
-			errRes.StatusCode = resp.StatusCode()
-			errRes.ErrorMessage = string(resp.Body())
-		}
-
-		return nil, errRes
-	}
+	return client.IsochroneContext(context.Background(), input)
+}
 
-	// Build geojson feature collection
-	fc := geojson.NewFeatureCollection()
-	if err := json.Unmarshal(resp.Body(), fc); err != nil {
-		return nil, fmt.Errorf("error while decoding http isochrone json response data: %w", err)
+// IsochroneContext is like Isochrone, the request is cancelled when ctx is done.
+func (client *Client) IsochroneContext(ctx context.Context, input *IsochroneInput) (*geojson.FeatureCollection, error) {
+	output := geojson.NewFeatureCollection()
+	if err := client.call(ctx, ActionIsochrone, input, output); err != nil {
+		return nil, err
 	}
 
-	return fc, nil
+	return output, nil
 }
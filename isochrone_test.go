@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gotidy/ptr"
+	"github.com/valyala/fasthttp"
 )
 
 func TestIsochrone(t *testing.T) {
@@ -23,3 +24,20 @@ func TestIsochrone(t *testing.T) {
 
 	t.Log(output)
 }
+
+func TestIsochroneRequestPath(t *testing.T) {
+	path := ""
+
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		path = string(ctx.Path())
+		ctx.SetBodyString(`{"type": "FeatureCollection", "features": []}`)
+	})
+
+	if _, err := clt.Isochrone(&IsochroneInput{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if path != "/"+ActionIsochrone {
+		t.Fatalf("expected request path /%s, got %s", ActionIsochrone, path)
+	}
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/angelodlfrtr/valhalla-http-client-go/polyline"
+)
+
+type kmlDocument struct {
+	XMLName  xml.Name `xml:"kml"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Document *kmlFolder
+}
+
+type kmlFolder struct {
+	XMLName    xml.Name        `xml:"Document"`
+	Name       string          `xml:"name,omitempty"`
+	Placemarks []*kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlLineString struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlPoint struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlPlacemark struct {
+	Name        string         `xml:"name,omitempty"`
+	Description string         `xml:"description,omitempty"`
+	LineString  *kmlLineString `xml:"LineString,omitempty"`
+	Point       *kmlPoint      `xml:"Point,omitempty"`
+}
+
+// WriteKML writes the route as a KML 2.2 document to w.
+// The document contains one LineString placemark per leg (from the decoded leg shape),
+// one Point placemark per trip location and one Point placemark per maneuver,
+// with the maneuver instruction as description.
+func (output *RouteOutput) WriteKML(w io.Writer) error {
+	folder := &kmlFolder{}
+	doc := &kmlDocument{XMLNS: "http://www.opengis.net/kml/2.2", Document: folder}
+
+	if output.ID != nil {
+		folder.Name = *output.ID
+	}
+
+	if output.Trip == nil {
+		return writeXML(w, doc)
+	}
+
+	for legIndex, leg := range output.Trip.Legs {
+		shape, err := leg.DecodedShape()
+		if err != nil {
+			return fmt.Errorf("unable to decode shape of leg %d: %w", legIndex, err)
+		}
+
+		folder.Placemarks = append(folder.Placemarks, &kmlPlacemark{
+			Name:       fmt.Sprintf("Leg %d", legIndex+1),
+			LineString: &kmlLineString{Tessellate: 1, Coordinates: kmlCoordinates(shape...)},
+		})
+
+		for _, maneuver := range leg.Maneuvers {
+			if maneuver == nil || maneuver.BeginShapeIndex == nil {
+				continue
+			}
+
+			index := *maneuver.BeginShapeIndex
+			if index < 0 || index >= len(shape) {
+				continue
+			}
+
+			placemark := &kmlPlacemark{
+				Name:  strings.Join(maneuver.StreetNames, ", "),
+				Point: &kmlPoint{Coordinates: kmlCoordinates(shape[index])},
+			}
+
+			if maneuver.Instruction != nil {
+				placemark.Description = *maneuver.Instruction
+			}
+
+			folder.Placemarks = append(folder.Placemarks, placemark)
+		}
+	}
+
+	for _, location := range output.Trip.Locations {
+		if location == nil || location.Lat == nil || location.Lon == nil {
+			continue
+		}
+
+		placemark := &kmlPlacemark{
+			Point: &kmlPoint{Coordinates: kmlCoordinates(polyline.Point{Lat: *location.Lat, Lon: *location.Lon})},
+		}
+
+		if location.Name != nil {
+			placemark.Name = *location.Name
+		}
+
+		folder.Placemarks = append(folder.Placemarks, placemark)
+	}
+
+	return writeXML(w, doc)
+}
+
+// kmlCoordinates formats points as a KML coordinates tuple list: "lon,lat lon,lat"
+func kmlCoordinates(points ...polyline.Point) string {
+	sb := strings.Builder{}
+	for i, point := range points {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+
+		sb.WriteString(formatCoordinate(point.Lon))
+		sb.WriteByte(',')
+		sb.WriteString(formatCoordinate(point.Lat))
+	}
+
+	return sb.String()
+}
@@ -1,3 +1,276 @@
 package client
 
-// @TODO
+import (
+	"context"
+)
+
+// LocateInput is the input for the locate service
+type LocateInput struct {
+	// Locations to correlate to the route network.
+	// Locations have the same format as for the route service.
+	Locations []*RouteLocation `json:"locations,omitempty"`
+
+	// Costing the costing model used to filter the candidate edges and nodes.
+	Costing *string `json:"costing,omitempty"`
+
+	// CostingOptions (optional) Costing options for the specified costing model.
+	CostingOptions *CostingModelOptions `json:"costing_options,omitempty"`
+
+	// Verbose if true, the full attribution of each correlated edge and node is returned.
+	// Default false.
+	Verbose *bool `json:"verbose,omitempty"`
+
+	// ID name your locate request. If id is specified, the naming will be sent thru to the response.
+	ID *string `json:"id,omitempty"`
+}
+
+// LocateOutputGraphID identifier of an edge or a node in the valhalla graph.
+type LocateOutputGraphID struct {
+	// Value the full graph id.
+	Value *uint64 `json:"value,omitempty"`
+
+	// ID the id of the element within its tile.
+	ID *uint64 `json:"id,omitempty"`
+
+	// TileID the id of the tile containing the element.
+	TileID *uint64 `json:"tile_id,omitempty"`
+
+	// Level the hierarchy level of the tile.
+	Level *int `json:"level,omitempty"`
+}
+
+// LocateOutputEdgeClassification classification of an edge (verbose only).
+type LocateOutputEdgeClassification struct {
+	// Classification road class, ex: motorway, primary, residential, service_other.
+	Classification *string `json:"classification,omitempty"`
+
+	// Use the use of the edge, ex: road, ramp, footway, cycleway.
+	Use *string `json:"use,omitempty"`
+
+	// Surface the surface of the edge, ex: paved_smooth, gravel.
+	Surface *string `json:"surface,omitempty"`
+
+	// Link true if the edge is a link (ramp or turn channel).
+	Link *bool `json:"link,omitempty"`
+
+	// Internal true if the edge is internal to an intersection.
+	Internal *bool `json:"internal,omitempty"`
+}
+
+// LocateOutputEdgeGeoAttributes geometric attributes of an edge (verbose only).
+type LocateOutputEdgeGeoAttributes struct {
+	// Length of the edge in meters.
+	Length *float64 `json:"length,omitempty"`
+
+	// WeightedGrade the weighted grade of the edge.
+	WeightedGrade *float64 `json:"weighted_grade,omitempty"`
+
+	// MaxUpSlope the maximum upward slope along the edge.
+	MaxUpSlope *float64 `json:"max_up_slope,omitempty"`
+
+	// MaxDownSlope the maximum downward slope along the edge.
+	MaxDownSlope *float64 `json:"max_down_slope,omitempty"`
+
+	// Curvature a measure of the edge curvature.
+	Curvature *float64 `json:"curvature,omitempty"`
+}
+
+// LocateOutputEdgeAttributes full attribution of an edge (verbose only).
+type LocateOutputEdgeAttributes struct {
+	// Classification of the edge.
+	Classification *LocateOutputEdgeClassification `json:"classification,omitempty"`
+
+	// GeoAttributes of the edge.
+	GeoAttributes *LocateOutputEdgeGeoAttributes `json:"geo_attributes,omitempty"`
+
+	// Access per mode of travel in the edge direction, ex: {"car": true, "pedestrian": false}.
+	Access map[string]bool `json:"access,omitempty"`
+
+	// EndNode graph id of the node at the end of the edge.
+	EndNode *LocateOutputGraphID `json:"end_node,omitempty"`
+
+	// Speed the speed of the edge in KPH.
+	Speed *int `json:"speed,omitempty"`
+
+	// SpeedType how the speed was determined: tagged or classified.
+	SpeedType *string `json:"speed_type,omitempty"`
+
+	// SpeedLimit posted speed limit in KPH, if any.
+	SpeedLimit *int `json:"speed_limit,omitempty"`
+
+	// LaneCount the number of lanes of the edge.
+	LaneCount *int `json:"lane_count,omitempty"`
+
+	// CycleLane type of cycle lane along the edge, ex: none, shared, dedicated, separated.
+	CycleLane *string `json:"cycle_lane,omitempty"`
+
+	// Forward true if the edge is in the forward direction of the way.
+	Forward *bool `json:"forward,omitempty"`
+
+	// Toll true if the edge is subject to a toll.
+	Toll *bool `json:"toll,omitempty"`
+
+	// Tunnel true if the edge is a tunnel.
+	Tunnel *bool `json:"tunnel,omitempty"`
+
+	// Bridge true if the edge is a bridge.
+	Bridge *bool `json:"bridge,omitempty"`
+
+	// Roundabout true if the edge is part of a roundabout.
+	Roundabout *bool `json:"roundabout,omitempty"`
+
+	// TrafficSignal true if there is a traffic signal at the end of the edge.
+	TrafficSignal *bool `json:"traffic_signal,omitempty"`
+
+	// CountryCrossing true if the edge crosses a country border.
+	CountryCrossing *bool `json:"country_crossing,omitempty"`
+
+	// DestinationOnly true if the edge is only accessible to reach a destination (ex: private roads).
+	DestinationOnly *bool `json:"destination_only,omitempty"`
+
+	// NotThru true if the edge leads to a region with no exit.
+	NotThru *bool `json:"not_thru,omitempty"`
+
+	// Unreachable true if the edge is not reachable from the rest of the graph.
+	Unreachable *bool `json:"unreachable,omitempty"`
+
+	// TruckRoute true if the edge is part of a truck network.
+	TruckRoute *bool `json:"truck_route,omitempty"`
+
+	// PartOfComplexRestriction true if the edge is part of a complex turn restriction.
+	PartOfComplexRestriction *bool `json:"part_of_complex_restriction,omitempty"`
+
+	// Shortcut true if the edge is a shortcut.
+	Shortcut *bool `json:"shortcut,omitempty"`
+}
+
+// LocateOutputEdgeInfo information shared by the two directions of an edge (verbose only).
+type LocateOutputEdgeInfo struct {
+	// WayID OpenStreetMap way id of the edge.
+	WayID *uint64 `json:"way_id,omitempty"`
+
+	// Names the names of the edge.
+	Names []string `json:"names,omitempty"`
+
+	// Shape an encoded polyline of the edge (with 6 digits decimal precision).
+	Shape *string `json:"shape,omitempty"`
+
+	// MeanElevation the mean elevation along the edge, in meters.
+	MeanElevation *float64 `json:"mean_elevation,omitempty"`
+
+	// BikeNetwork the bike network the edge belongs to, if any.
+	BikeNetwork *int `json:"bike_network,omitempty"`
+
+	// SpeedLimit posted speed limit in KPH, if any.
+	SpeedLimit *int `json:"speed_limit,omitempty"`
+}
+
+// LocateOutputEdge an edge correlated to an input location.
+type LocateOutputEdge struct {
+	// WayID OpenStreetMap way id of the edge.
+	WayID *uint64 `json:"way_id,omitempty"`
+
+	// CorrelatedLat latitude of the location projected on the edge.
+	CorrelatedLat *float64 `json:"correlated_lat,omitempty"`
+
+	// CorrelatedLon longitude of the location projected on the edge.
+	CorrelatedLon *float64 `json:"correlated_lon,omitempty"`
+
+	// PercentAlong position of the projected location along the edge, from 0 to 1.
+	PercentAlong *float64 `json:"percent_along,omitempty"`
+
+	// SideOfStreet side of the edge the input location lies on: left, right or neither.
+	SideOfStreet *string `json:"side_of_street,omitempty"`
+
+	// Next parameters are only returned in verbose mode.
+
+	// EdgeID graph id of the edge.
+	EdgeID *LocateOutputGraphID `json:"edge_id,omitempty"`
+
+	// Distance between the input location and the projected location, in meters.
+	Distance *float64 `json:"distance,omitempty"`
+
+	// Heading of the edge at the projected location, in degrees from north.
+	Heading *float64 `json:"heading,omitempty"`
+
+	// OutboundReach number of nodes reachable from the edge.
+	OutboundReach *int `json:"outbound_reach,omitempty"`
+
+	// InboundReach number of nodes the edge can be reached from.
+	InboundReach *int `json:"inbound_reach,omitempty"`
+
+	// LinearReference base64 encoded OpenLR location reference of the edge.
+	LinearReference *string `json:"linear_reference,omitempty"`
+
+	// Edge the full attribution of the edge.
+	Edge *LocateOutputEdgeAttributes `json:"edge,omitempty"`
+
+	// EdgeInfo the information shared by the two directions of the edge.
+	EdgeInfo *LocateOutputEdgeInfo `json:"edge_info,omitempty"`
+}
+
+// LocateOutputNodeAttributes full attribution of a node (verbose only).
+type LocateOutputNodeAttributes struct {
+	// Type of the node, ex: street_intersection, gate, bollard, toll_booth.
+	Type *string `json:"type,omitempty"`
+
+	// EdgeCount the number of edges leaving the node.
+	EdgeCount *int `json:"edge_count,omitempty"`
+
+	// Access per mode of travel, ex: {"car": true, "pedestrian": true}.
+	Access map[string]bool `json:"access,omitempty"`
+
+	// TrafficSignal true if there is a traffic signal at the node.
+	TrafficSignal *bool `json:"traffic_signal,omitempty"`
+
+	// Elevation of the node, in meters.
+	Elevation *float64 `json:"elevation,omitempty"`
+}
+
+// LocateOutputNode a node correlated to an input location.
+type LocateOutputNode struct {
+	// Lat latitude of the node.
+	Lat *float64 `json:"lat,omitempty"`
+
+	// Lon longitude of the node.
+	Lon *float64 `json:"lon,omitempty"`
+
+	// Next parameters are only returned in verbose mode.
+
+	// NodeID graph id of the node.
+	NodeID *LocateOutputGraphID `json:"node_id,omitempty"`
+
+	// Node the full attribution of the node.
+	Node *LocateOutputNodeAttributes `json:"node,omitempty"`
+}
+
+// LocateOutput correlation result for one input location.
+type LocateOutput struct {
+	// InputLat latitude of the input location.
+	InputLat *float64 `json:"input_lat,omitempty"`
+
+	// InputLon longitude of the input location.
+	InputLon *float64 `json:"input_lon,omitempty"`
+
+	// Edges the edges the location was correlated to. Nil if none was found.
+	Edges []*LocateOutputEdge `json:"edges,omitempty"`
+
+	// Nodes the nodes the location was correlated to. Nil if none was found.
+	Nodes []*LocateOutputNode `json:"nodes,omitempty"`
+}
+
+// Locate returns the edges and nodes of the route network the given locations correlate to.
+// The output contains one result per input location, in the same order.
+func (client *Client) Locate(input *LocateInput) ([]*LocateOutput, error) {
+	return client.LocateContext(context.Background(), input)
+}
+
+// LocateContext is like Locate, the request is cancelled when ctx is done.
+func (client *Client) LocateContext(ctx context.Context, input *LocateInput) ([]*LocateOutput, error) {
+	output := []*LocateOutput{}
+	if err := client.call(ctx, ActionLocate, input, &output); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
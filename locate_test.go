@@ -0,0 +1,29 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/gotidy/ptr"
+)
+
+func TestLocate(t *testing.T) {
+	input := &LocateInput{
+		Costing: ptr.String(CostingModelAuto),
+		Verbose: ptr.Bool(true),
+	}
+
+	input.Locations = append(input.Locations, &RouteLocation{Lat: ptr.Float64(48.390394), Lon: ptr.Float64(-4.486076)})
+
+	clt := getTestClient()
+
+	output, err := clt.Locate(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(output) != len(input.Locations) {
+		t.Fatalf("expected %d results, got %d", len(input.Locations), len(output))
+	}
+
+	t.Log(output)
+}
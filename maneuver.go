@@ -0,0 +1,257 @@
+package client
+
+import (
+	"strconv"
+)
+
+// ManeuverType type of a route maneuver, as the valhalla maneuver codes.
+// See https://valhalla.github.io/valhalla/api/turn-by-turn/api-reference/#maneuver-types
+type ManeuverType int
+
+// Maneuver types
+const (
+	ManeuverTypeNone                             ManeuverType = 0
+	ManeuverTypeStart                            ManeuverType = 1
+	ManeuverTypeStartRight                       ManeuverType = 2
+	ManeuverTypeStartLeft                        ManeuverType = 3
+	ManeuverTypeDestination                      ManeuverType = 4
+	ManeuverTypeDestinationRight                 ManeuverType = 5
+	ManeuverTypeDestinationLeft                  ManeuverType = 6
+	ManeuverTypeBecomes                          ManeuverType = 7
+	ManeuverTypeContinue                         ManeuverType = 8
+	ManeuverTypeSlightRight                      ManeuverType = 9
+	ManeuverTypeRight                            ManeuverType = 10
+	ManeuverTypeSharpRight                       ManeuverType = 11
+	ManeuverTypeUturnRight                       ManeuverType = 12
+	ManeuverTypeUturnLeft                        ManeuverType = 13
+	ManeuverTypeSharpLeft                        ManeuverType = 14
+	ManeuverTypeLeft                             ManeuverType = 15
+	ManeuverTypeSlightLeft                       ManeuverType = 16
+	ManeuverTypeRampStraight                     ManeuverType = 17
+	ManeuverTypeRampRight                        ManeuverType = 18
+	ManeuverTypeRampLeft                         ManeuverType = 19
+	ManeuverTypeExitRight                        ManeuverType = 20
+	ManeuverTypeExitLeft                         ManeuverType = 21
+	ManeuverTypeStayStraight                     ManeuverType = 22
+	ManeuverTypeStayRight                        ManeuverType = 23
+	ManeuverTypeStayLeft                         ManeuverType = 24
+	ManeuverTypeMerge                            ManeuverType = 25
+	ManeuverTypeRoundaboutEnter                  ManeuverType = 26
+	ManeuverTypeRoundaboutExit                   ManeuverType = 27
+	ManeuverTypeFerryEnter                       ManeuverType = 28
+	ManeuverTypeFerryExit                        ManeuverType = 29
+	ManeuverTypeTransit                          ManeuverType = 30
+	ManeuverTypeTransitTransfer                  ManeuverType = 31
+	ManeuverTypeTransitRemainOn                  ManeuverType = 32
+	ManeuverTypeTransitConnectionStart           ManeuverType = 33
+	ManeuverTypeTransitConnectionTransfer        ManeuverType = 34
+	ManeuverTypeTransitConnectionDestination     ManeuverType = 35
+	ManeuverTypePostTransitConnectionDestination ManeuverType = 36
+	ManeuverTypeMergeRight                       ManeuverType = 37
+	ManeuverTypeMergeLeft                        ManeuverType = 38
+	ManeuverTypeElevatorEnter                    ManeuverType = 39
+	ManeuverTypeStepsEnter                       ManeuverType = 40
+	ManeuverTypeEscalatorEnter                   ManeuverType = 41
+	ManeuverTypeBuildingEnter                    ManeuverType = 42
+	ManeuverTypeBuildingExit                     ManeuverType = 43
+)
+
+var maneuverTypeNames = []string{
+	"none",
+	"start",
+	"start_right",
+	"start_left",
+	"destination",
+	"destination_right",
+	"destination_left",
+	"becomes",
+	"continue",
+	"slight_right",
+	"right",
+	"sharp_right",
+	"uturn_right",
+	"uturn_left",
+	"sharp_left",
+	"left",
+	"slight_left",
+	"ramp_straight",
+	"ramp_right",
+	"ramp_left",
+	"exit_right",
+	"exit_left",
+	"stay_straight",
+	"stay_right",
+	"stay_left",
+	"merge",
+	"roundabout_enter",
+	"roundabout_exit",
+	"ferry_enter",
+	"ferry_exit",
+	"transit",
+	"transit_transfer",
+	"transit_remain_on",
+	"transit_connection_start",
+	"transit_connection_transfer",
+	"transit_connection_destination",
+	"post_transit_connection_destination",
+	"merge_right",
+	"merge_left",
+	"elevator_enter",
+	"steps_enter",
+	"escalator_enter",
+	"building_enter",
+	"building_exit",
+}
+
+// String returns the name of the maneuver type (ie: "slight_right")
+func (t ManeuverType) String() string {
+	if t < 0 || int(t) >= len(maneuverTypeNames) {
+		return "ManeuverType(" + strconv.Itoa(int(t)) + ")"
+	}
+
+	return maneuverTypeNames[t]
+}
+
+// IsStart returns true for maneuvers starting the route
+func (t ManeuverType) IsStart() bool {
+	return t >= ManeuverTypeStart && t <= ManeuverTypeStartLeft
+}
+
+// IsArrival returns true for maneuvers arriving at a destination
+func (t ManeuverType) IsArrival() bool {
+	return t >= ManeuverTypeDestination && t <= ManeuverTypeDestinationLeft
+}
+
+// IsTurn returns true for turns, from slight to sharp turns and u-turns, on both sides
+func (t ManeuverType) IsTurn() bool {
+	return t >= ManeuverTypeSlightRight && t <= ManeuverTypeSlightLeft
+}
+
+// IsRamp returns true for ramp and exit maneuvers
+func (t ManeuverType) IsRamp() bool {
+	return t >= ManeuverTypeRampStraight && t <= ManeuverTypeExitLeft
+}
+
+// IsRoundabout returns true for roundabout enter and exit maneuvers
+func (t ManeuverType) IsRoundabout() bool {
+	return t == ManeuverTypeRoundaboutEnter || t == ManeuverTypeRoundaboutExit
+}
+
+// IsTransit returns true for transit maneuvers, including connections to and from transit stations
+func (t ManeuverType) IsTransit() bool {
+	return t >= ManeuverTypeTransit && t <= ManeuverTypePostTransitConnectionDestination
+}
+
+// TravelMode travel mode of a maneuver or an edge.
+// Marshaled as its name in json (ie: "drive").
+type TravelMode int
+
+// Travel modes
+const (
+	TravelModeUnknown TravelMode = iota
+	TravelModeDrive
+	TravelModePedestrian
+	TravelModeBicycle
+	TravelModeTransit
+)
+
+var travelModeNames = []string{"", "drive", "pedestrian", "bicycle", "transit"}
+
+// String returns the name of the travel mode
+func (mode TravelMode) String() string {
+	if mode <= TravelModeUnknown || int(mode) >= len(travelModeNames) {
+		return "unknown"
+	}
+
+	return travelModeNames[mode]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// TravelModeUnknown is marshaled as "unknown", so decoded responses can be encoded again.
+func (mode TravelMode) MarshalText() ([]byte, error) {
+	return []byte(mode.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// Unsupported names are decoded as TravelModeUnknown.
+func (mode *TravelMode) UnmarshalText(text []byte) error {
+	*mode = TravelMode(indexOfName(travelModeNames, string(text)))
+	return nil
+}
+
+// TravelType travel type of a maneuver, refining its travel mode (ie: bus, wheelchair).
+// Marshaled as its name in json (ie: "motor_scooter").
+type TravelType int
+
+// Travel types
+const (
+	TravelTypeUnknown TravelType = iota
+
+	// Drive travel types
+	TravelTypeCar
+	TravelTypeMotorcycle
+	TravelTypeBus
+	TravelTypeTractorTrailer
+	TravelTypeMotorScooter
+
+	// Pedestrian travel types
+	TravelTypeFoot
+	TravelTypeWheelchair
+	TravelTypeSegway
+
+	// Bicycle travel types
+	TravelTypeRoad
+	TravelTypeCross
+	TravelTypeHybrid
+	TravelTypeMountain
+
+	// Transit travel types, bus is shared with drive
+	TravelTypeTram
+	TravelTypeMetro
+	TravelTypeRail
+	TravelTypeFerry
+	TravelTypeCableCar
+	TravelTypeGondola
+	TravelTypeFunicular
+)
+
+var travelTypeNames = []string{
+	"",
+	"car", "motorcycle", "bus", "tractor_trailer", "motor_scooter",
+	"foot", "wheelchair", "segway",
+	"road", "cross", "hybrid", "mountain",
+	"tram", "metro", "rail", "ferry", "cable_car", "gondola", "funicular",
+}
+
+// String returns the name of the travel type
+func (travelType TravelType) String() string {
+	if travelType <= TravelTypeUnknown || int(travelType) >= len(travelTypeNames) {
+		return "unknown"
+	}
+
+	return travelTypeNames[travelType]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// TravelTypeUnknown is marshaled as "unknown", so decoded responses can be encoded again.
+func (travelType TravelType) MarshalText() ([]byte, error) {
+	return []byte(travelType.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// Unsupported names are decoded as TravelTypeUnknown.
+func (travelType *TravelType) UnmarshalText(text []byte) error {
+	*travelType = TravelType(indexOfName(travelTypeNames, string(text)))
+	return nil
+}
+
+// indexOfName returns the index of name in names, 0 if not found
+func indexOfName(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+
+	return 0
+}
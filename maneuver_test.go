@@ -0,0 +1,65 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+func maneuverTypePtr(t ManeuverType) *ManeuverType {
+	return &t
+}
+
+func TestManeuverType(t *testing.T) {
+	if ManeuverTypeDestinationRight.String() != "destination_right" || ManeuverType(99).String() != "ManeuverType(99)" {
+		t.Fatalf("unexpected names %s %s", ManeuverTypeDestinationRight, ManeuverType(99))
+	}
+
+	if len(maneuverTypeNames) != int(ManeuverTypeBuildingExit)+1 {
+		t.Fatal("expected a name for each maneuver type")
+	}
+
+	if !ManeuverTypeSharpLeft.IsTurn() || ManeuverTypeRampLeft.IsTurn() {
+		t.Fatal("unexpected IsTurn")
+	}
+
+	if !ManeuverTypeDestinationLeft.IsArrival() || ManeuverTypeTransitConnectionDestination.IsArrival() {
+		t.Fatal("unexpected IsArrival")
+	}
+
+	if !ManeuverTypeTransitConnectionStart.IsTransit() || ManeuverTypeFerryEnter.IsTransit() {
+		t.Fatal("unexpected IsTransit")
+	}
+}
+
+func TestRouteOutputManeuverJSON(t *testing.T) {
+	maneuver := &RouteOutputManeuver{}
+	data := []byte(`{"type":26,"travel_mode":"transit","travel_type":"cable_car"}`)
+
+	if err := json.Unmarshal(data, maneuver); err != nil {
+		t.Fatal(err)
+	}
+
+	if *maneuver.Type != ManeuverTypeRoundaboutEnter || *maneuver.TravelMode != TravelModeTransit || *maneuver.TravelType != TravelTypeCableCar {
+		t.Fatalf("unexpected maneuver %s %s %s", maneuver.Type, maneuver.TravelMode, maneuver.TravelType)
+	}
+
+	out, err := json.Marshal(maneuver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(out) != string(data) {
+		t.Fatalf("expected %s, got %s", data, out)
+	}
+
+	maneuver = &RouteOutputManeuver{}
+	if err := json.Unmarshal([]byte(`{"travel_type":"hovercraft"}`), maneuver); err != nil || *maneuver.TravelType != TravelTypeUnknown {
+		t.Fatalf("expected unknown travel type, got %v %v", maneuver.TravelType, err)
+	}
+
+	out, err = json.Marshal(maneuver)
+	if err != nil || string(out) != `{"travel_type":"unknown"}` {
+		t.Fatalf("expected unknown travel type to be marshaled, got %s %v", out, err)
+	}
+}
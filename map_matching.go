@@ -1,3 +1,435 @@
 package client
 
-// @TODO
+import (
+	"context"
+
+	"github.com/angelodlfrtr/valhalla-http-client-go/polyline"
+)
+
+const (
+	// ShapeMatchEdgeWalk indicates an edge walking algorithm can be used.
+	// This algorithm requires nearly exact shape matching, so it should only be
+	// used when the shape is from a prior Valhalla route.
+	ShapeMatchEdgeWalk string = "edge_walk"
+
+	// ShapeMatchMapSnap indicates that a map-matching algorithm should be used
+	// because the input shape might not closely match Valhalla edges.
+	// This algorithm is more expensive.
+	ShapeMatchMapSnap string = "map_snap"
+
+	// ShapeMatchWalkOrSnap will try edge walking and if this does not succeed,
+	// it will fall back and use map matching. This is the default.
+	ShapeMatchWalkOrSnap string = "walk_or_snap"
+)
+
+const (
+	TraceAttributesFilterActionInclude string = "include"
+	TraceAttributesFilterActionExclude string = "exclude"
+)
+
+const (
+	TraceAttributesFilterEdgeNames           string = "edge.names"
+	TraceAttributesFilterEdgeLength          string = "edge.length"
+	TraceAttributesFilterEdgeSpeed           string = "edge.speed"
+	TraceAttributesFilterEdgeSpeedLimit      string = "edge.speed_limit"
+	TraceAttributesFilterEdgeRoadClass       string = "edge.road_class"
+	TraceAttributesFilterEdgeUse             string = "edge.use"
+	TraceAttributesFilterEdgeWayID           string = "edge.way_id"
+	TraceAttributesFilterEdgeID              string = "edge.id"
+	TraceAttributesFilterEdgeBeginShapeIndex string = "edge.begin_shape_index"
+	TraceAttributesFilterEdgeEndShapeIndex   string = "edge.end_shape_index"
+	TraceAttributesFilterEdgeTravelMode      string = "edge.travel_mode"
+	TraceAttributesFilterNodeElapsedTime     string = "node.elapsed_time"
+	TraceAttributesFilterMatchedPoint        string = "matched.point"
+	TraceAttributesFilterMatchedType         string = "matched.type"
+	TraceAttributesFilterMatchedEdgeIndex    string = "matched.edge_index"
+	TraceAttributesFilterShape               string = "shape"
+	TraceAttributesFilterConfidenceScore     string = "confidence_score"
+)
+
+const (
+	TraceAttributesMatchedPointTypeUnmatched    string = "unmatched"
+	TraceAttributesMatchedPointTypeInterpolated string = "interpolated"
+	TraceAttributesMatchedPointTypeMatched      string = "matched"
+)
+
+// TracePoint a point of the input trace.
+type TracePoint struct {
+	// Lat latitude of the point in degrees.
+	Lat *float64 `json:"lat"`
+
+	// Lon longitude of the point in degrees.
+	Lon *float64 `json:"lon"`
+
+	// Time (optional) timestamp of the point, in seconds since epoch.
+	Time *int64 `json:"time,omitempty"`
+
+	// Radius (optional) search radius in meters for this point,
+	// overrides trace_options.search_radius.
+	Radius *int `json:"radius,omitempty"`
+
+	// Type (optional) of the point, either break, through, via or break_through.
+	// See RouteLocation.Type.
+	Type *string `json:"type,omitempty"`
+}
+
+// TraceOptions options for the map matching algorithm.
+type TraceOptions struct {
+	// SearchRadius search radius in meters associated with supplied trace points.
+	SearchRadius *float64 `json:"search_radius,omitempty"`
+
+	// GPSAccuracy GPS accuracy in meters associated with supplied trace points.
+	GPSAccuracy *float64 `json:"gps_accuracy,omitempty"`
+
+	// BreakageDistance breaking distance in meters between trace points.
+	BreakageDistance *float64 `json:"breakage_distance,omitempty"`
+
+	// InterpolationDistance interpolation distance in meters beyond which trace points
+	// are merged together.
+	InterpolationDistance *float64 `json:"interpolation_distance,omitempty"`
+
+	// TurnPenaltyFactor penalizes turns from one road segment to next.
+	TurnPenaltyFactor *float64 `json:"turn_penalty_factor,omitempty"`
+}
+
+// TraceInput is the input shared by the map matching services
+type TraceInput struct {
+	// Shape list of points of the trace. Either shape or encoded_polyline must be set.
+	Shape []*TracePoint `json:"shape,omitempty"`
+
+	// EncodedPolyline is a string of a polyline-encoded, with the specified precision, shape.
+	// Either shape or encoded_polyline must be set.
+	EncodedPolyline *string `json:"encoded_polyline,omitempty"`
+
+	// ShapeFormat specifies whether the polyline is encoded with 6 digit precision (polyline6)
+	// or 5 digit precision (polyline5).
+	// If shape_format is not specified, the encoded polyline is expected to be 6 digit precision.
+	ShapeFormat *string `json:"shape_format,omitempty"`
+
+	// Costing the costing model used to match the trace: auto, bicycle, bus, pedestrian.
+	Costing *string `json:"costing,omitempty"`
+
+	// CostingOptions (optional) Costing options for the specified costing model.
+	CostingOptions *CostingModelOptions `json:"costing_options,omitempty"`
+
+	// ShapeMatch the algorithm used to match the trace: edge_walk, map_snap or walk_or_snap.
+	ShapeMatch *string `json:"shape_match,omitempty"`
+
+	// TraceOptions options for the map matching algorithm.
+	TraceOptions *TraceOptions `json:"trace_options,omitempty"`
+
+	// BeginTime (with encoded_polyline only) timestamp of the first point of the trace,
+	// in seconds since epoch.
+	BeginTime *int64 `json:"begin_time,omitempty"`
+
+	// Durations (with encoded_polyline only) list of durations in seconds between
+	// each point of the trace.
+	Durations []float64 `json:"durations,omitempty"`
+
+	// UseTimestamps if true, the timestamps (or durations) of the input are used
+	// to compute the elapsed time along the matched path. Default false.
+	UseTimestamps *bool `json:"use_timestamps,omitempty"`
+
+	// Units distance units for output.
+	// Allowable unit types are miles (or mi) and kilometers (or km).
+	// If no unit type is specified, the units default to kilometers.
+	Units *string `json:"units,omitempty"`
+
+	// ID name your request. If id is specified, the naming will be sent thru to the response.
+	ID *string `json:"id,omitempty"`
+}
+
+// TraceRouteInput is the input for the trace route service
+type TraceRouteInput struct {
+	TraceInput
+
+	// Language of the narration instructions based on the IETF BCP 47 language tag string.
+	Language *string `json:"language,omitempty"`
+
+	// DirectionsType none, maneuvers or instructions. See RouteInput.DirectionsType.
+	DirectionsType *string `json:"directions_type,omitempty"`
+
+	// LinearReferences when present and true, the successful response will include a key
+	// linear_references. See RouteInput.LinearReferences.
+	LinearReferences *bool `json:"linear_references,omitempty"`
+}
+
+// TraceAttributesFilters filters the attributes returned by the trace attributes service.
+type TraceAttributesFilters struct {
+	// Attributes list of attribute keys, ex: edge.names, matched.point, shape.
+	Attributes []string `json:"attributes,omitempty"`
+
+	// Action either include or exclude the listed attributes.
+	Action *string `json:"action,omitempty"`
+}
+
+// TraceAttributesInput is the input for the trace attributes service
+type TraceAttributesInput struct {
+	TraceInput
+
+	// Filters (optional) restrict the attributes returned. All attributes are returned by default.
+	Filters *TraceAttributesFilters `json:"filters,omitempty"`
+}
+
+// TraceAttributesOutputIntersectingEdge an edge intersecting the path at a node.
+type TraceAttributesOutputIntersectingEdge struct {
+	// BeginHeading the direction at the beginning of the intersecting edge, in degrees.
+	BeginHeading *int `json:"begin_heading,omitempty"`
+
+	// FromEdgeNameConsistency true if the intersecting edge shares a name with the inbound edge.
+	FromEdgeNameConsistency *bool `json:"from_edge_name_consistency,omitempty"`
+
+	// ToEdgeNameConsistency true if the intersecting edge shares a name with the outbound edge.
+	ToEdgeNameConsistency *bool `json:"to_edge_name_consistency,omitempty"`
+
+	// Driveability forward, backward or both.
+	Driveability *string `json:"driveability,omitempty"`
+
+	// Cyclability forward, backward or both.
+	Cyclability *string `json:"cyclability,omitempty"`
+
+	// Walkability forward, backward or both.
+	Walkability *string `json:"walkability,omitempty"`
+
+	// Use the use of the intersecting edge.
+	Use *string `json:"use,omitempty"`
+
+	// RoadClass the road class of the intersecting edge.
+	RoadClass *string `json:"road_class,omitempty"`
+}
+
+// TraceAttributesOutputNode the node at the end of a matched edge.
+type TraceAttributesOutputNode struct {
+	// IntersectingEdges the edges intersecting the path at this node.
+	IntersectingEdges []*TraceAttributesOutputIntersectingEdge `json:"intersecting_edges,omitempty"`
+
+	// ElapsedTime elapsed time of the path to arrive at this node, in seconds.
+	ElapsedTime *float64 `json:"elapsed_time,omitempty"`
+
+	// AdminIndex index into the admins list.
+	AdminIndex *int `json:"admin_index,omitempty"`
+
+	// Type of the node, ex: street_intersection, gate, toll_booth.
+	Type *string `json:"type,omitempty"`
+
+	// Fork true if the node is a fork.
+	Fork *bool `json:"fork,omitempty"`
+
+	// TimeZone the time zone of the node.
+	TimeZone *string `json:"time_zone,omitempty"`
+}
+
+// TraceAttributesOutputEdge an edge of the matched path.
+type TraceAttributesOutputEdge struct {
+	// Names list of names of the edge.
+	Names []string `json:"names,omitempty"`
+
+	// Length of the edge in the units specified.
+	Length *float64 `json:"length,omitempty"`
+
+	// Speed actual speed of the edge in the units specified, per hour.
+	Speed *float64 `json:"speed,omitempty"`
+
+	// SpeedLimit posted speed limit in the units specified, per hour.
+	SpeedLimit *float64 `json:"speed_limit,omitempty"`
+
+	// RoadClass road class of the edge, ex: motorway, primary, residential.
+	RoadClass *string `json:"road_class,omitempty"`
+
+	// BeginHeading the direction at the beginning of the edge, in degrees.
+	BeginHeading *int `json:"begin_heading,omitempty"`
+
+	// EndHeading the direction at the end of the edge, in degrees.
+	EndHeading *int `json:"end_heading,omitempty"`
+
+	// BeginShapeIndex index into the list of shape points for the start of the edge.
+	BeginShapeIndex *int `json:"begin_shape_index,omitempty"`
+
+	// EndShapeIndex index into the list of shape points for the end of the edge.
+	EndShapeIndex *int `json:"end_shape_index,omitempty"`
+
+	// Traversability forward, backward or both.
+	Traversability *string `json:"traversability,omitempty"`
+
+	// Use the use of the edge, ex: road, ramp, footway.
+	Use *string `json:"use,omitempty"`
+
+	// Surface the surface of the edge, ex: paved_smooth, gravel.
+	Surface *string `json:"surface,omitempty"`
+
+	// Toll true if the edge has a toll.
+	Toll *bool `json:"toll,omitempty"`
+
+	// Unpaved true if the edge is unpaved or has rough pavement.
+	Unpaved *bool `json:"unpaved,omitempty"`
+
+	// Tunnel true if the edge is a tunnel.
+	Tunnel *bool `json:"tunnel,omitempty"`
+
+	// Bridge true if the edge is a bridge.
+	Bridge *bool `json:"bridge,omitempty"`
+
+	// Roundabout true if the edge is part of a roundabout.
+	Roundabout *bool `json:"roundabout,omitempty"`
+
+	// InternalIntersection true if the edge is internal to an intersection.
+	InternalIntersection *bool `json:"internal_intersection,omitempty"`
+
+	// DriveOnRight true if vehicles drive on the right side of the edge.
+	DriveOnRight *bool `json:"drive_on_right,omitempty"`
+
+	// TravelMode travel mode on the edge: drive, pedestrian, bicycle, transit.
+	TravelMode *TravelMode `json:"travel_mode,omitempty"`
+
+	// VehicleType vehicle type when travel mode is drive.
+	VehicleType *string `json:"vehicle_type,omitempty"`
+
+	// PedestrianType pedestrian type when travel mode is pedestrian.
+	PedestrianType *string `json:"pedestrian_type,omitempty"`
+
+	// BicycleType bicycle type when travel mode is bicycle.
+	BicycleType *string `json:"bicycle_type,omitempty"`
+
+	// ID the graph id of the edge.
+	ID *uint64 `json:"id,omitempty"`
+
+	// WayID OpenStreetMap way id of the edge.
+	WayID *uint64 `json:"way_id,omitempty"`
+
+	// WeightedGrade the weighted grade factor of the edge.
+	WeightedGrade *float64 `json:"weighted_grade,omitempty"`
+
+	// MaxUpwardGrade the maximum upward slope along the edge, in percent.
+	MaxUpwardGrade *int `json:"max_upward_grade,omitempty"`
+
+	// MaxDownwardGrade the maximum downward slope along the edge, in percent.
+	MaxDownwardGrade *int `json:"max_downward_grade,omitempty"`
+
+	// MeanElevation the mean elevation along the edge, in the units specified.
+	MeanElevation *float64 `json:"mean_elevation,omitempty"`
+
+	// LaneCount the number of lanes of the edge.
+	LaneCount *int `json:"lane_count,omitempty"`
+
+	// CycleLane type of cycle lane along the edge, ex: none, shared, dedicated, separated.
+	CycleLane *string `json:"cycle_lane,omitempty"`
+
+	// Sidewalk side(s) of the edge having a sidewalk: left, right, both or none.
+	Sidewalk *string `json:"sidewalk,omitempty"`
+
+	// Density the relative density along the edge, from 0 to 15.
+	Density *int `json:"density,omitempty"`
+
+	// SourcePercentAlong (first edge only) position of the start of the path along the edge.
+	SourcePercentAlong *float64 `json:"source_percent_along,omitempty"`
+
+	// TargetPercentAlong (last edge only) position of the end of the path along the edge.
+	TargetPercentAlong *float64 `json:"target_percent_along,omitempty"`
+
+	// EndNode the node at the end of the edge.
+	EndNode *TraceAttributesOutputNode `json:"end_node,omitempty"`
+}
+
+// TraceAttributesOutputMatchedPoint the matching result of an input point.
+type TraceAttributesOutputMatchedPoint struct {
+	// Lat latitude of the matched point.
+	Lat *float64 `json:"lat,omitempty"`
+
+	// Lon longitude of the matched point.
+	Lon *float64 `json:"lon,omitempty"`
+
+	// Type of the matched point: unmatched, interpolated or matched.
+	Type *string `json:"type,omitempty"`
+
+	// EdgeIndex index of the edge the point was matched to, in the edges list.
+	EdgeIndex *int `json:"edge_index,omitempty"`
+
+	// BeginRouteDiscontinuity true if the point is the beginning of a discontinuity in the path.
+	BeginRouteDiscontinuity *bool `json:"begin_route_discontinuity,omitempty"`
+
+	// EndRouteDiscontinuity true if the point is the end of a discontinuity in the path.
+	EndRouteDiscontinuity *bool `json:"end_route_discontinuity,omitempty"`
+
+	// DistanceAlongEdge position of the matched point along the edge, from 0 to 1.
+	DistanceAlongEdge *float64 `json:"distance_along_edge,omitempty"`
+
+	// DistanceFromTracePoint distance in meters between the input point and the matched point.
+	DistanceFromTracePoint *float64 `json:"distance_from_trace_point,omitempty"`
+}
+
+// TraceAttributesOutputAdmin administrative area crossed by the matched path.
+type TraceAttributesOutputAdmin struct {
+	CountryCode *string `json:"country_code,omitempty"`
+	CountryText *string `json:"country_text,omitempty"`
+	StateCode   *string `json:"state_code,omitempty"`
+	StateText   *string `json:"state_text,omitempty"`
+}
+
+// TraceAttributesOutput is the output for the trace attributes service
+type TraceAttributesOutput struct {
+	// ID from the id in request
+	ID *string `json:"id,omitempty"`
+
+	// Units distance units used in output.
+	Units *string `json:"units,omitempty"`
+
+	// Shape an encoded polyline of the matched path (with 6 digits decimal precision).
+	Shape *string `json:"shape,omitempty"`
+
+	// ConfidenceScore confidence of the match, from 0 to 1.
+	ConfidenceScore *float64 `json:"confidence_score,omitempty"`
+
+	// RawScore raw score of the match.
+	RawScore *float64 `json:"raw_score,omitempty"`
+
+	// OSMChangeset identifier of the OpenStreetMap base data version.
+	OSMChangeset *int64 `json:"osm_changeset,omitempty"`
+
+	// Edges list of the edges of the matched path.
+	Edges []*TraceAttributesOutputEdge `json:"edges,omitempty"`
+
+	// MatchedPoints list of the matching result of each input point, in the same order.
+	MatchedPoints []*TraceAttributesOutputMatchedPoint `json:"matched_points,omitempty"`
+
+	// Admins list of the administrative areas crossed by the matched path.
+	Admins []*TraceAttributesOutputAdmin `json:"admins,omitempty"`
+}
+
+// DecodedShape returns the decoded points of the matched path shape
+func (output *TraceAttributesOutput) DecodedShape() ([]polyline.Point, error) {
+	if output.Shape == nil {
+		return nil, nil
+	}
+
+	return polyline.Decode(*output.Shape, polyline.Precision6)
+}
+
+// TraceRoute returns the route matching the given trace, with turn by turn directions.
+func (client *Client) TraceRoute(input *TraceRouteInput) (*RouteOutput, error) {
+	return client.TraceRouteContext(context.Background(), input)
+}
+
+// TraceRouteContext is like TraceRoute, the request is cancelled when ctx is done.
+func (client *Client) TraceRouteContext(ctx context.Context, input *TraceRouteInput) (*RouteOutput, error) {
+	output := &RouteOutput{}
+	if err := client.call(ctx, ActionTraceRoute, input, output); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// TraceAttributes returns the attributes of the edges and points matching the given trace.
+func (client *Client) TraceAttributes(input *TraceAttributesInput) (*TraceAttributesOutput, error) {
+	return client.TraceAttributesContext(context.Background(), input)
+}
+
+// TraceAttributesContext is like TraceAttributes, the request is cancelled when ctx is done.
+func (client *Client) TraceAttributesContext(ctx context.Context, input *TraceAttributesInput) (*TraceAttributesOutput, error) {
+	output := &TraceAttributesOutput{}
+	if err := client.call(ctx, ActionTraceAttributes, input, output); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
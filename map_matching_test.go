@@ -0,0 +1,66 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gotidy/ptr"
+)
+
+func getTestTrace() TraceInput {
+	input := TraceInput{
+		Costing:    ptr.String(CostingModelAuto),
+		ShapeMatch: ptr.String(ShapeMatchMapSnap),
+		TraceOptions: &TraceOptions{
+			SearchRadius: ptr.Float64(50),
+		},
+	}
+
+	input.Shape = append(input.Shape, &TracePoint{Lat: ptr.Float64(48.390394), Lon: ptr.Float64(-4.486076), Time: ptr.Int64(1660000000)})
+	input.Shape = append(input.Shape, &TracePoint{Lat: ptr.Float64(48.390794), Lon: ptr.Float64(-4.485316), Time: ptr.Int64(1660000010)})
+	input.Shape = append(input.Shape, &TracePoint{Lat: ptr.Float64(48.391228), Lon: ptr.Float64(-4.484515), Time: ptr.Int64(1660000020)})
+
+	return input
+}
+
+func TestTraceRoute(t *testing.T) {
+	input := &TraceRouteInput{TraceInput: getTestTrace()}
+
+	clt := getTestClient()
+
+	output, err := clt.TraceRoute(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(output)
+}
+
+func TestTraceAttributes(t *testing.T) {
+	input := &TraceAttributesInput{
+		TraceInput: getTestTrace(),
+		Filters: &TraceAttributesFilters{
+			Attributes: []string{TraceAttributesFilterEdgeNames, TraceAttributesFilterMatchedPoint},
+			Action:     ptr.String(TraceAttributesFilterActionInclude),
+		},
+	}
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(body), `"shape_match":"map_snap"`) || !strings.Contains(string(body), `"filters":`) {
+		t.Fatalf("unexpected trace attributes input json: %s", body)
+	}
+
+	clt := getTestClient()
+
+	output, err := clt.TraceAttributes(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(output)
+}
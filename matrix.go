@@ -1,3 +1,155 @@
 package client
 
-// @TODO
+import (
+	"context"
+
+	"github.com/goccy/go-json"
+)
+
+// MatrixInput is the input for the time distance matrix service (sources_to_targets action)
+type MatrixInput struct {
+	// Sources list of starting locations. Locations have the same format as for the
+	// route service, at a minimum each location must include latitude and longitude.
+	Sources []*RouteLocation `json:"sources,omitempty"`
+
+	// Targets list of ending locations. Locations have the same format as for the
+	// route service, at a minimum each location must include latitude and longitude.
+	Targets []*RouteLocation `json:"targets,omitempty"`
+
+	// Costing the time distance matrix service uses the auto, bicycle, pedestrian,
+	// motor_scooter and other costing models available in the Turn-by-Turn service.
+	// Multimodal costing is not supported.
+	Costing *string `json:"costing,omitempty"`
+
+	// CostingOptions (optional) Costing options for the specified costing model.
+	CostingOptions *CostingModelOptions `json:"costing_options,omitempty"`
+
+	// Units distance units for output.
+	// Allowable unit types are miles (or mi) and kilometers (or km).
+	// If no unit type is specified, the units default to kilometers.
+	Units *string `json:"units,omitempty"`
+
+	// DateTime this is the local date and time at the locations.
+	// When set, each cell of the output will contain the date time at the target.
+	// See DepartAt, ArriveBy, Now and Invariant.
+	DateTime *DateTime `json:"date_time,omitempty"`
+
+	// MatrixLocations only applicable to one-to-many or many-to-one requests.
+	// This defaults to all locations. When specified explicitly, this option allows a partial
+	// result to be returned. This is basically equivalent to "find the closest/best locations
+	// out of the full set".
+	MatrixLocations *int `json:"matrix_locations,omitempty"`
+
+	// Format response format: json (default) or pbf.
+	// Pbf responses are decoded into the same output, without sources and targets.
+	Format *string `json:"format,omitempty"`
+
+	// ID name your matrix request. If id is specified, the naming will be sent thru to the response.
+	ID *string `json:"id,omitempty"`
+}
+
+// MatrixOutputCell is one cell of the time distance matrix.
+type MatrixOutputCell struct {
+	// FromIndex the index of the source location.
+	FromIndex *int `json:"from_index,omitempty"`
+
+	// ToIndex the index of the target location.
+	ToIndex *int `json:"to_index,omitempty"`
+
+	// Time the computed time between the source and target, in seconds.
+	// Nil when the target is not reachable from the source.
+	Time *float64 `json:"time,omitempty"`
+
+	// Distance the computed distance between the source and target,
+	// in the units specified in input. Nil when the target is not reachable from the source.
+	Distance *float64 `json:"distance,omitempty"`
+
+	// DateTime (only when date_time is set in input) the local date and time at the target,
+	// using the ISO 8601 format (YYYY-MM-DDThh:mm). See ArrivalTime.
+	DateTime *string `json:"date_time,omitempty"`
+
+	// TimeZoneOffset (only when date_time is set in input) the time zone offset at the target.
+	TimeZoneOffset *string `json:"time_zone_offset,omitempty"`
+
+	// TimeZoneName (only when date_time is set in input) the time zone name at the target.
+	TimeZoneName *string `json:"time_zone_name,omitempty"`
+}
+
+// Reachable returns true if a path was found between the source and the target of the cell
+func (cell *MatrixOutputCell) Reachable() bool {
+	return cell != nil && cell.Time != nil && cell.Distance != nil
+}
+
+// MatrixOutputLocations list of locations returned by the matrix service.
+// Older valhalla versions nest each location in its own array, both forms are accepted.
+type MatrixOutputLocations []*RouteLocation
+
+// UnmarshalJSON decodes flat or nested list of locations
+func (locations *MatrixOutputLocations) UnmarshalJSON(data []byte) error {
+	flat := []*RouteLocation{}
+	if err := json.Unmarshal(data, &flat); err == nil {
+		*locations = flat
+		return nil
+	}
+
+	nested := [][]*RouteLocation{}
+	if err := json.Unmarshal(data, &nested); err != nil {
+		return err
+	}
+
+	*locations = make(MatrixOutputLocations, 0, len(nested))
+	for _, locs := range nested {
+		*locations = append(*locations, locs...)
+	}
+
+	return nil
+}
+
+// MatrixOutput is the output for the time distance matrix service
+type MatrixOutput struct {
+	// ID from the id in request
+	ID *string `json:"id,omitempty"`
+
+	// Units distance units used in output.
+	Units *string `json:"units,omitempty"`
+
+	// SourcesToTargets the matrix of cells, one row per source and one column per target.
+	SourcesToTargets [][]*MatrixOutputCell `json:"sources_to_targets,omitempty"`
+
+	// Sources the list of source locations, as correlated by the service.
+	// Nil for pbf responses (see MatrixInput.Format), which are not decoded.
+	Sources MatrixOutputLocations `json:"sources,omitempty"`
+
+	// Targets the list of target locations, as correlated by the service.
+	// Nil for pbf responses (see MatrixInput.Format), which are not decoded.
+	Targets MatrixOutputLocations `json:"targets,omitempty"`
+}
+
+// Cell returns the cell for given source and target indexes, nil if out of bounds
+func (output *MatrixOutput) Cell(source, target int) *MatrixOutputCell {
+	if source < 0 || source >= len(output.SourcesToTargets) {
+		return nil
+	}
+
+	row := output.SourcesToTargets[source]
+	if target < 0 || target >= len(row) {
+		return nil
+	}
+
+	return row[target]
+}
+
+// Matrix returns the time and distance between each source and target locations.
+func (client *Client) Matrix(input *MatrixInput) (*MatrixOutput, error) {
+	return client.MatrixContext(context.Background(), input)
+}
+
+// MatrixContext is like Matrix, the request is cancelled when ctx is done.
+func (client *Client) MatrixContext(ctx context.Context, input *MatrixInput) (*MatrixOutput, error) {
+	output := &MatrixOutput{}
+	if err := client.call(ctx, ActionMatrix, input, output); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gotidy/ptr"
+)
+
+func TestMatrix(t *testing.T) {
+	input := &MatrixInput{
+		Costing: ptr.String(CostingModelAuto),
+		Units:   ptr.String("km"),
+	}
+
+	input.Sources = append(input.Sources, &RouteLocation{Lat: ptr.Float64(48.390394), Lon: ptr.Float64(-4.486076)})
+	input.Targets = append(input.Targets, &RouteLocation{Lat: ptr.Float64(48.45252), Lon: ptr.Float64(-4.25252)})
+	input.Targets = append(input.Targets, &RouteLocation{Lat: ptr.Float64(48.40912), Lon: ptr.Float64(-4.39826)})
+
+	clt := getTestClient()
+
+	output, err := clt.Matrix(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !output.Cell(0, 1).Reachable() {
+		t.Fatal("expected target 1 to be reachable from source 0")
+	}
+
+	t.Log(output)
+}
+
+func TestMatrixOutputDecode(t *testing.T) {
+	body := `{
+		"sources_to_targets": [[
+			{"distance": 0, "time": 0, "from_index": 0, "to_index": 0},
+			{"distance": null, "time": null, "from_index": 0, "to_index": 1}
+		]],
+		"sources": [[{"lat": 48.39, "lon": -4.48}]],
+		"targets": [{"lat": 48.39, "lon": -4.48}, {"lat": 48.45, "lon": -4.25}],
+		"units": "kilometers"
+	}`
+
+	output := &MatrixOutput{}
+	if err := json.Unmarshal([]byte(body), output); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(output.Sources) != 1 || len(output.Targets) != 2 {
+		t.Fatalf("unexpected locations count: %d sources, %d targets", len(output.Sources), len(output.Targets))
+	}
+
+	if !output.Cell(0, 0).Reachable() {
+		t.Fatal("expected cell 0,0 to be reachable")
+	}
+
+	if output.Cell(0, 1).Reachable() {
+		t.Fatal("expected cell 0,1 to be unreachable")
+	}
+
+	if output.Cell(1, 0) != nil {
+		t.Fatal("expected out of bounds cell to be nil")
+	}
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+
+	"github.com/valyala/fasthttp"
+)
+
+// RoundTrip is a single http exchange with a valhalla endpoint
+type RoundTrip struct {
+	// Action the valhalla action called, ex: ActionRoute.
+	Action string
+
+	// Endpoint the base url of the endpoint called.
+	Endpoint string
+
+	// Attempt the attempt number of the request, starting at 1, see RetryPolicy.
+	Attempt int
+
+	// Request the request sent, with body, auth and custom headers set.
+	Request *fasthttp.Request
+
+	// Response the response received, filled by the innermost RoundTripper.
+	Response *fasthttp.Response
+
+	// transportErr is set by the innermost RoundTripper when no response was received
+	transportErr bool
+}
+
+// RoundTripper sends rt request and fills rt response. The returned error is either
+// a transport error or an *ErrorResponse decoded from a non 200 response.
+type RoundTripper func(ctx context.Context, rt *RoundTrip) error
+
+// Middleware wraps a RoundTripper, allowing to inspect or modify the request before
+// calling next, and the response, error and timing after
+type Middleware func(next RoundTripper) RoundTripper
+
+// Use appends middlewares to the client chain. The first middleware is the outermost.
+// Use must not be called concurrently with requests.
+func (client *Client) Use(middlewares ...Middleware) {
+	client.middlewares = append(client.middlewares, middlewares...)
+}
+
+// roundTripper returns the innermost round tripper wrapped by the client middlewares
+func (client *Client) roundTripper() RoundTripper {
+	next := client.roundTrip
+	for i := len(client.middlewares) - 1; i >= 0; i-- {
+		next = client.middlewares[i](next)
+	}
+
+	return next
+}
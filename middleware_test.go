@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestMiddlewares(t *testing.T) {
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		if string(ctx.Request.Header.Peek("X-Trace")) != "abc" {
+			ctx.Error("missing trace header", fasthttp.StatusBadRequest)
+			return
+		}
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetBodyString(`{"error_code": 171, "error": "No suitable edges near location", "status_code": 400, "status": "Bad Request"}`)
+	})
+
+	order := []string{}
+	var elapsed time.Duration
+	var gotErr error
+
+	clt.Use(
+		func(next RoundTripper) RoundTripper {
+			return func(ctx context.Context, rt *RoundTrip) error {
+				order = append(order, "outer")
+				start := time.Now()
+				err := next(ctx, rt)
+				elapsed = time.Since(start)
+				gotErr = err
+
+				return err
+			}
+		},
+		func(next RoundTripper) RoundTripper {
+			return func(ctx context.Context, rt *RoundTrip) error {
+				order = append(order, "inner:"+rt.Action)
+				rt.Request.Header.Set("X-Trace", "abc")
+
+				return next(ctx, rt)
+			}
+		},
+	)
+
+	_, err := clt.Route(&RouteInput{})
+
+	errRes := &ErrorResponse{}
+	if !errors.As(err, &errRes) || errRes.ErrorCode != 171 {
+		t.Fatalf("expected error response with code 171, got %v", err)
+	}
+
+	if !errors.As(gotErr, &errRes) {
+		t.Fatalf("expected middleware to see decoded error, got %v", gotErr)
+	}
+
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner:"+ActionRoute {
+		t.Fatalf("unexpected middlewares order %v", order)
+	}
+
+	if elapsed <= 0 {
+		t.Fatal("expected elapsed time to be measured")
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		ctx.Error("should not be called", fasthttp.StatusInternalServerError)
+	})
+
+	clt.Use(func(next RoundTripper) RoundTripper {
+		return func(ctx context.Context, rt *RoundTrip) error {
+			rt.Response.SetStatusCode(fasthttp.StatusOK)
+			rt.Response.SetBodyString(`{"id": "cached"}`)
+
+			return nil
+		}
+	})
+
+	output, err := clt.Route(&RouteInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if output.ID == nil || *output.ID != "cached" {
+		t.Fatalf("expected response from middleware, got %v", output)
+	}
+}
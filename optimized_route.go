@@ -1,3 +1,47 @@
 package client
 
-// @TODO
+import (
+	"context"
+	"fmt"
+)
+
+// OptimizedRoute returns the route visiting all the given locations in the optimal order.
+// First and last locations are kept in place, intermediate locations are reordered.
+// Each location of the output trip has its OriginalIndex set to its index in input.
+func (client *Client) OptimizedRoute(input *RouteInput) (*RouteOutput, error) {
+	return client.OptimizedRouteContext(context.Background(), input)
+}
+
+// OptimizedRouteContext is like OptimizedRoute, the request is cancelled when ctx is done.
+func (client *Client) OptimizedRouteContext(ctx context.Context, input *RouteInput) (*RouteOutput, error) {
+	if err := input.validateOutputFormat(); err != nil {
+		return nil, fmt.Errorf("%s: %w", ActionOptimizedRoute, err)
+	}
+
+	output := &RouteOutput{}
+	if err := client.call(ctx, ActionOptimizedRoute, input, output); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// LocationsOrder returns the visiting order of the trip locations, as indexes in
+// the input locations. Returns nil if the output has no trip or if any location
+// lacks its original index (ie: output not from OptimizedRoute).
+func (output *RouteOutput) LocationsOrder() []int {
+	if output.Trip == nil {
+		return nil
+	}
+
+	order := make([]int, 0, len(output.Trip.Locations))
+	for _, location := range output.Trip.Locations {
+		if location == nil || location.OriginalIndex == nil {
+			return nil
+		}
+
+		order = append(order, *location.OriginalIndex)
+	}
+
+	return order
+}
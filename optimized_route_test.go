@@ -0,0 +1,34 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/gotidy/ptr"
+)
+
+func TestOptimizedRoute(t *testing.T) {
+	input := &RouteInput{
+		Locations: []*RouteLocation{},
+		Costing:   ptr.String(CostingModelAuto),
+		Units:     ptr.String("km"),
+	}
+
+	input.Locations = append(input.Locations, &RouteLocation{Lat: ptr.Float64(48.390394), Lon: ptr.Float64(-4.486076)})
+	input.Locations = append(input.Locations, &RouteLocation{Lat: ptr.Float64(48.45252), Lon: ptr.Float64(-4.25252)})
+	input.Locations = append(input.Locations, &RouteLocation{Lat: ptr.Float64(48.40912), Lon: ptr.Float64(-4.39826)})
+	input.Locations = append(input.Locations, &RouteLocation{Lat: ptr.Float64(48.390394), Lon: ptr.Float64(-4.486076)})
+
+	clt := getTestClient()
+
+	output, err := clt.OptimizedRoute(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order := output.LocationsOrder()
+	if len(order) != len(input.Locations) {
+		t.Fatalf("expected %d locations in order, got %v", len(input.Locations), order)
+	}
+
+	t.Log(order)
+}
@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+)
+
+// OSRMCodeOk code of successful OSRM responses
+const OSRMCodeOk string = "Ok"
+
+// OSRMLane a lane of an intersection.
+type OSRMLane struct {
+	// Indications indications (ie: left, straight) of the lane.
+	Indications []string `json:"indications,omitempty"`
+
+	// Valid whether the lane is a valid choice for the current maneuver.
+	Valid *bool `json:"valid,omitempty"`
+
+	// Active whether the lane is the preferred choice for the current maneuver.
+	Active *bool `json:"active,omitempty"`
+}
+
+// OSRMIntersection an intersection passed along a step.
+type OSRMIntersection struct {
+	// Location [lon, lat] of the intersection.
+	Location []float64 `json:"location,omitempty"`
+
+	// Bearings bearings of the roads at the intersection, in degrees clockwise from north.
+	Bearings []int `json:"bearings,omitempty"`
+
+	// Entry for each road in bearings, whether it is allowed to enter it.
+	Entry []bool `json:"entry,omitempty"`
+
+	// In index in bearings of the road used to enter the intersection.
+	In *int `json:"in,omitempty"`
+
+	// Out index in bearings of the road used to leave the intersection.
+	Out *int `json:"out,omitempty"`
+
+	// Classes classes (ie: toll, motorway, ferry, tunnel) of the road leaving the intersection.
+	Classes []string `json:"classes,omitempty"`
+
+	// Lanes lanes available at the intersection.
+	Lanes []*OSRMLane `json:"lanes,omitempty"`
+}
+
+// OSRMStepManeuver the maneuver at the beginning of a step.
+type OSRMStepManeuver struct {
+	// Location [lon, lat] of the maneuver.
+	Location []float64 `json:"location,omitempty"`
+
+	// BearingBefore bearing before the maneuver, in degrees clockwise from north.
+	BearingBefore *int `json:"bearing_before,omitempty"`
+
+	// BearingAfter bearing after the maneuver, in degrees clockwise from north.
+	BearingAfter *int `json:"bearing_after,omitempty"`
+
+	// Type type of the maneuver (ie: depart, turn, roundabout, arrive).
+	Type *string `json:"type,omitempty"`
+
+	// Modifier direction change of the maneuver (ie: left, slight right, uturn).
+	Modifier *string `json:"modifier,omitempty"`
+
+	// Exit number of the roundabout exit to take.
+	Exit *int `json:"exit,omitempty"`
+
+	// Instruction narrative instruction of the maneuver.
+	Instruction *string `json:"instruction,omitempty"`
+}
+
+// OSRMRouteStep a step of a leg, from one maneuver to the next.
+type OSRMRouteStep struct {
+	// Distance distance of the step in meters.
+	Distance *float64 `json:"distance,omitempty"`
+
+	// Duration duration of the step in seconds.
+	Duration *float64 `json:"duration,omitempty"`
+
+	// Weight weight of the step.
+	Weight *float64 `json:"weight,omitempty"`
+
+	// Name name of the road of the step.
+	Name *string `json:"name,omitempty"`
+
+	// Ref reference number or code of the road of the step.
+	Ref *string `json:"ref,omitempty"`
+
+	// Destinations destinations of the road of the step.
+	Destinations *string `json:"destinations,omitempty"`
+
+	// Exits exit numbers or names of the road of the step.
+	Exits *string `json:"exits,omitempty"`
+
+	// Mode mode of transportation of the step (ie: driving, walking, cycling, ferry).
+	Mode *string `json:"mode,omitempty"`
+
+	// DrivingSide side of the road traffic drives on: left or right.
+	DrivingSide *string `json:"driving_side,omitempty"`
+
+	// Geometry geometry of the step, an encoded polyline by default.
+	Geometry *string `json:"geometry,omitempty"`
+
+	// Maneuver the maneuver at the beginning of the step.
+	Maneuver *OSRMStepManeuver `json:"maneuver,omitempty"`
+
+	// Intersections the intersections passed along the step.
+	Intersections []*OSRMIntersection `json:"intersections,omitempty"`
+
+	// RotaryName name of the rotary, for rotary maneuvers.
+	RotaryName *string `json:"rotary_name,omitempty"`
+}
+
+// OSRMAnnotation per segment metadata of a leg, each list has one entry per shape segment.
+type OSRMAnnotation struct {
+	// Distance distance of each segment in meters.
+	Distance []float64 `json:"distance,omitempty"`
+
+	// Duration duration of each segment in seconds.
+	Duration []float64 `json:"duration,omitempty"`
+
+	// Speed speed of each segment in meters per second.
+	Speed []float64 `json:"speed,omitempty"`
+
+	// Weight weight of each segment.
+	Weight []float64 `json:"weight,omitempty"`
+
+	// Nodes ids of the nodes along the leg.
+	Nodes []int64 `json:"nodes,omitempty"`
+}
+
+// OSRMRouteLeg a leg of a route, between two waypoints.
+type OSRMRouteLeg struct {
+	// Distance distance of the leg in meters.
+	Distance *float64 `json:"distance,omitempty"`
+
+	// Duration duration of the leg in seconds.
+	Duration *float64 `json:"duration,omitempty"`
+
+	// Weight weight of the leg.
+	Weight *float64 `json:"weight,omitempty"`
+
+	// Summary names of the main roads of the leg.
+	Summary *string `json:"summary,omitempty"`
+
+	// Steps the steps of the leg.
+	Steps []*OSRMRouteStep `json:"steps,omitempty"`
+
+	// Annotation per segment metadata of the leg.
+	Annotation *OSRMAnnotation `json:"annotation,omitempty"`
+}
+
+// OSRMRoute a route between the waypoints.
+type OSRMRoute struct {
+	// Distance distance of the route in meters.
+	Distance *float64 `json:"distance,omitempty"`
+
+	// Duration duration of the route in seconds.
+	Duration *float64 `json:"duration,omitempty"`
+
+	// Weight weight of the route.
+	Weight *float64 `json:"weight,omitempty"`
+
+	// WeightName name of the weight metric.
+	WeightName *string `json:"weight_name,omitempty"`
+
+	// Geometry geometry of the route, an encoded polyline by default.
+	Geometry *string `json:"geometry,omitempty"`
+
+	// Legs the legs of the route.
+	Legs []*OSRMRouteLeg `json:"legs,omitempty"`
+}
+
+// OSRMWaypoint an input location snapped to the road network.
+type OSRMWaypoint struct {
+	// Name name of the road the location is snapped to.
+	Name *string `json:"name,omitempty"`
+
+	// Location [lon, lat] of the snapped location.
+	Location []float64 `json:"location,omitempty"`
+
+	// Distance distance in meters between the input and the snapped location.
+	Distance *float64 `json:"distance,omitempty"`
+}
+
+// OSRMRouteOutput a route response in the OSRM format.
+type OSRMRouteOutput struct {
+	// ID from the id in request
+	ID *string `json:"id,omitempty"`
+
+	// Code response code, OSRMCodeOk if the request succeeded.
+	Code *string `json:"code,omitempty"`
+
+	// Message error message if the request failed.
+	Message *string `json:"message,omitempty"`
+
+	// Routes the route and its alternates.
+	Routes []*OSRMRoute `json:"routes,omitempty"`
+
+	// Waypoints the snapped input locations.
+	Waypoints []*OSRMWaypoint `json:"waypoints,omitempty"`
+}
+
+// osrmRouteInput a route input requesting a response in the OSRM format
+type osrmRouteInput RouteInput
+
+// osrmErrorResponse body of the error responses of requests in the OSRM format
+type osrmErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// RouteOSRM returns the route between the given locations, in the OSRM response format.
+// The Format of input is ignored.
+func (client *Client) RouteOSRM(input *RouteInput) (*OSRMRouteOutput, error) {
+	return client.RouteOSRMContext(context.Background(), input)
+}
+
+// RouteOSRMContext is like RouteOSRM, the request is cancelled when ctx is done.
+func (client *Client) RouteOSRMContext(ctx context.Context, input *RouteInput) (*OSRMRouteOutput, error) {
+	format := FormatOSRM
+	osrmInput := *input
+	osrmInput.Format = &format
+
+	output := &OSRMRouteOutput{}
+	if err := client.call(ctx, ActionRoute, (*osrmRouteInput)(&osrmInput), output); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRouteOSRM(t *testing.T) {
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		if !bytes.Contains(ctx.PostBody(), []byte(`"format":"osrm"`)) {
+			ctx.Error("missing format", fasthttp.StatusBadRequest)
+			return
+		}
+
+		ctx.SetBodyString(`{
+			"code": "Ok",
+			"routes": [{
+				"distance": 1224.6, "duration": 152.3, "weight": 160.1, "weight_name": "auto",
+				"geometry": "_p~iF~ps|U_ulLnnqC_mqNvxq` + "`" + `@",
+				"legs": [{
+					"distance": 1224.6, "duration": 152.3, "weight": 160.1, "summary": "Rue de Siam",
+					"annotation": {"distance": [10.5, 20.1], "duration": [1.2, 2.4]},
+					"steps": [{
+						"distance": 1224.6, "duration": 152.3, "name": "Rue de Siam", "mode": "driving",
+						"driving_side": "right", "geometry": "_p~iF~ps|U",
+						"maneuver": {"location": [-4.486076, 48.390394], "bearing_before": 0, "bearing_after": 90,
+							"type": "depart", "instruction": "Drive east on Rue de Siam."},
+						"intersections": [{"location": [-4.486076, 48.390394], "bearings": [90], "entry": [true], "out": 0,
+							"lanes": [{"indications": ["straight"], "valid": true}]}]
+					}]
+				}]
+			}],
+			"waypoints": [
+				{"name": "Rue de Siam", "location": [-4.486076, 48.390394], "distance": 3.1},
+				{"name": "", "location": [-4.25252, 48.45252], "distance": 0.4}
+			]
+		}`)
+	})
+
+	input := &RouteInput{
+		Locations: []*RouteLocation{
+			{Lat: ptr.Float64(48.390394), Lon: ptr.Float64(-4.486076)},
+			{Lat: ptr.Float64(48.45252), Lon: ptr.Float64(-4.25252)},
+		},
+		Costing: ptr.String(CostingModelAuto),
+	}
+
+	output, err := clt.RouteOSRM(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if input.Format != nil {
+		t.Fatal("expected input to be left unchanged")
+	}
+
+	if *output.Code != OSRMCodeOk || len(output.Routes) != 1 || len(output.Waypoints) != 2 {
+		t.Fatalf("unexpected output %+v", output)
+	}
+
+	step := output.Routes[0].Legs[0].Steps[0]
+	if *step.Maneuver.Type != "depart" || *step.Maneuver.BearingAfter != 90 {
+		t.Fatalf("unexpected step maneuver %+v", step.Maneuver)
+	}
+
+	if !*step.Intersections[0].Lanes[0].Valid || len(output.Routes[0].Legs[0].Annotation.Distance) != 2 {
+		t.Fatalf("unexpected step %+v", step)
+	}
+}
+
+func TestRouteOSRMError(t *testing.T) {
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{Endpoint: "http://valhalla.local", ValidateInputs: true},
+		map[string]fasthttp.RequestHandler{"valhalla.local": func(ctx *fasthttp.RequestCtx) {
+			ctx.SetStatusCode(fasthttp.StatusBadRequest)
+			ctx.SetBodyString(`{"code": "NoRoute", "message": "Impossible route between points"}`)
+		}},
+	)
+
+	input := &RouteInput{
+		Locations: []*RouteLocation{
+			{Lat: ptr.Float64(48.390394), Lon: ptr.Float64(-4.486076)},
+			{Lat: ptr.Float64(48.45252), Lon: ptr.Float64(-4.25252)},
+		},
+		Costing: ptr.String(CostingModelAuto),
+	}
+
+	_, err := clt.RouteOSRM(input)
+
+	errRes := &ErrorResponse{}
+	if !errors.As(err, &errRes) {
+		t.Fatalf("expected error response, got %v", err)
+	}
+
+	if errRes.OSRMCode != "NoRoute" || errRes.ErrorMessage != "Impossible route between points" ||
+		errRes.StatusCode != fasthttp.StatusBadRequest || errRes.ErrorCode != 0 {
+		t.Fatalf("unexpected error response %+v", errRes)
+	}
+}
+
+func TestRouteFormatOSRM(t *testing.T) {
+	var calls int32
+
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		atomic.AddInt32(&calls, 1)
+		ctx.SetBodyString(`{"code": "Ok"}`)
+	})
+
+	for _, format := range []string{FormatOSRM, FormatGPX} {
+		input := &RouteInput{Format: ptr.String(format)}
+
+		if _, err := clt.Route(input); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected invalid input error for %s format, got %v", format, err)
+		}
+
+		if _, err := clt.OptimizedRoute(input); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected invalid input error for %s format, got %v", format, err)
+		}
+	}
+
+	if atomic.LoadInt32(&calls) != 0 {
+		t.Fatal("expected osrm and gpx route requests not to be sent")
+	}
+}
@@ -0,0 +1,299 @@
+// Package pbf reads the protocol buffers wire format, without schema
+// nor reflection. It is used to decode valhalla pbf responses (api.proto) without
+// depending on a protobuf runtime.
+// See https://protobuf.dev/programming-guides/encoding/
+package pbf
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// WireType type of a field value on the wire
+type WireType uint8
+
+// Wire types
+const (
+	WireVarint  WireType = 0
+	WireFixed64 WireType = 1
+	WireBytes   WireType = 2
+	WireFixed32 WireType = 5
+)
+
+// ErrMalformed is returned when reading invalid protocol buffers data
+var ErrMalformed = errors.New("malformed protocol buffers data")
+
+// Reader reads the fields of an encoded message.
+//
+//	r := pbf.NewReader(data)
+//	for r.Next() {
+//		switch r.Field() {
+//		case 1:
+//			name = r.String()
+//		default:
+//			r.Skip()
+//		}
+//	}
+//	if err := r.Err(); err != nil {
+//		// handle error
+//	}
+//
+// Each field value must be read or skipped once after Next.
+// Reading a value with a wire type not matching the field sets ErrMalformed.
+type Reader struct {
+	data     []byte
+	pos      int
+	field    int
+	wireType WireType
+	err      error
+	parent   *Reader
+}
+
+// NewReader returns a reader of the message encoded in data
+func NewReader(data []byte) *Reader {
+	return &Reader{data: data}
+}
+
+// Reset resets the reader to read the message encoded in data
+func (r *Reader) Reset(data []byte) {
+	*r = Reader{data: data}
+}
+
+// Next reads the key of the next field, returns false at the end of the message or on error
+func (r *Reader) Next() bool {
+	if r.err != nil || r.pos >= len(r.data) {
+		return false
+	}
+
+	key := r.varint()
+	if r.err != nil {
+		return false
+	}
+
+	r.field = int(key >> 3)
+	r.wireType = WireType(key & 7)
+
+	if r.field == 0 {
+		r.fail(ErrMalformed)
+		return false
+	}
+
+	return true
+}
+
+// Field returns the number of the current field
+func (r *Reader) Field() int {
+	return r.field
+}
+
+// WireType returns the wire type of the current field
+func (r *Reader) WireType() WireType {
+	return r.wireType
+}
+
+// Err returns the first error met while reading
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Skip skips the value of the current field
+func (r *Reader) Skip() {
+	switch r.wireType {
+	case WireVarint:
+		r.varint()
+	case WireFixed64:
+		r.advance(8)
+	case WireFixed32:
+		r.advance(4)
+	case WireBytes:
+		r.bytes()
+	default:
+		r.fail(ErrMalformed)
+	}
+}
+
+// Uint64 reads a uint64, uint32 or enum value
+func (r *Reader) Uint64() uint64 {
+	if !r.expect(WireVarint) {
+		return 0
+	}
+
+	return r.varint()
+}
+
+// Uint32 reads a uint32 value
+func (r *Reader) Uint32() uint32 {
+	return uint32(r.Uint64())
+}
+
+// Int64 reads an int64 or int32 value
+func (r *Reader) Int64() int64 {
+	return int64(r.Uint64())
+}
+
+// Sint64 reads a zigzag encoded sint64 or sint32 value
+func (r *Reader) Sint64() int64 {
+	v := r.Uint64()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+// Bool reads a bool value
+func (r *Reader) Bool() bool {
+	return r.Uint64() != 0
+}
+
+// Double reads a double value
+func (r *Reader) Double() float64 {
+	if !r.expect(WireFixed64) {
+		return 0
+	}
+
+	b := r.advance(8)
+	if b == nil {
+		return 0
+	}
+
+	return math.Float64frombits(binary.LittleEndian.Uint64(b))
+}
+
+// Float reads a float value
+func (r *Reader) Float() float32 {
+	if !r.expect(WireFixed32) {
+		return 0
+	}
+
+	b := r.advance(4)
+	if b == nil {
+		return 0
+	}
+
+	return math.Float32frombits(binary.LittleEndian.Uint32(b))
+}
+
+// Bytes reads a bytes or embedded message value.
+// The returned slice references the reader data, it is not copied.
+func (r *Reader) Bytes() []byte {
+	if !r.expect(WireBytes) {
+		return nil
+	}
+
+	return r.bytes()
+}
+
+// String reads a string value
+func (r *Reader) String() string {
+	return string(r.Bytes())
+}
+
+// Message returns a reader of the embedded message value.
+// Errors met while reading the embedded message are also reported by r.
+func (r *Reader) Message() *Reader {
+	return &Reader{data: r.Bytes(), parent: r}
+}
+
+// PackedUint32 appends the values of a repeated uint32 field to values.
+// Both packed and non packed encodings are supported.
+func (r *Reader) PackedUint32(values []uint32) []uint32 {
+	if r.wireType == WireVarint {
+		return append(values, r.Uint32())
+	}
+
+	packed := NewReader(r.Bytes())
+	for packed.pos < len(packed.data) && packed.err == nil {
+		values = append(values, uint32(packed.varint()))
+	}
+
+	if packed.err != nil {
+		r.fail(packed.err)
+	}
+
+	return values
+}
+
+// PackedFloat appends the values of a repeated float field to values.
+// Both packed and non packed encodings are supported.
+func (r *Reader) PackedFloat(values []float32) []float32 {
+	if r.wireType == WireFixed32 {
+		return append(values, r.Float())
+	}
+
+	b := r.Bytes()
+	if len(b)%4 != 0 {
+		r.fail(ErrMalformed)
+		return values
+	}
+
+	for i := 0; i < len(b); i += 4 {
+		values = append(values, math.Float32frombits(binary.LittleEndian.Uint32(b[i:])))
+	}
+
+	return values
+}
+
+// fail sets err as the reader error and the error of its parents, if not already set
+func (r *Reader) fail(err error) {
+	for ; r != nil; r = r.parent {
+		if r.err == nil {
+			r.err = err
+		}
+	}
+}
+
+// expect sets ErrMalformed if the current field wire type is not wireType
+func (r *Reader) expect(wireType WireType) bool {
+	if r.err != nil {
+		return false
+	}
+
+	if r.wireType != wireType {
+		r.fail(ErrMalformed)
+		return false
+	}
+
+	return true
+}
+
+// varint reads a varint at the current position
+func (r *Reader) varint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		r.fail(ErrMalformed)
+		return 0
+	}
+
+	r.pos += n
+	return v
+}
+
+// bytes reads a length delimited value at the current position
+func (r *Reader) bytes() []byte {
+	length := r.varint()
+	if r.err != nil {
+		return nil
+	}
+
+	if length > uint64(len(r.data)-r.pos) {
+		r.fail(ErrMalformed)
+		return nil
+	}
+
+	return r.advance(int(length))
+}
+
+// advance returns the next n bytes and moves the position after them
+func (r *Reader) advance(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+
+	if n > len(r.data)-r.pos {
+		r.fail(ErrMalformed)
+		return nil
+	}
+
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+
+	return b
+}
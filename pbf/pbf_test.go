@@ -0,0 +1,116 @@
+package pbf_test
+
+import (
+	"testing"
+
+	"github.com/angelodlfrtr/valhalla-http-client-go/internal/pbftest"
+	"github.com/angelodlfrtr/valhalla-http-client-go/pbf"
+)
+
+func TestReaderRoundTrip(t *testing.T) {
+	nested := pbftest.AppendString(nil, 1, "nested")
+
+	data := pbftest.AppendUint64(nil, 1, 300)
+	data = pbftest.AppendString(data, 2, "hello")
+	data = pbftest.AppendDouble(data, 3, 48.390394)
+	data = pbftest.AppendFloat(data, 4, 1.5)
+	data = pbftest.AppendBool(data, 5, true)
+	data = pbftest.AppendBytes(data, 6, nested)
+	data = pbftest.AppendPackedUint32(data, 7, []uint32{1, 200, 70000})
+	data = pbftest.AppendUint64(data, 7, 5)
+	data = pbftest.AppendPackedFloat(data, 8, []float32{0.5, 2})
+	data = pbftest.AppendUint64(data, 99, 1)
+
+	var (
+		u       uint64
+		s       string
+		d       float64
+		f       float32
+		b       bool
+		n       string
+		uints   []uint32
+		floats  []float32
+		skipped int
+	)
+
+	r := pbf.NewReader(data)
+	for r.Next() {
+		switch r.Field() {
+		case 1:
+			u = r.Uint64()
+		case 2:
+			s = r.String()
+		case 3:
+			d = r.Double()
+		case 4:
+			f = r.Float()
+		case 5:
+			b = r.Bool()
+		case 6:
+			m := r.Message()
+			for m.Next() {
+				n = m.String()
+			}
+		case 7:
+			uints = r.PackedUint32(uints)
+		case 8:
+			floats = r.PackedFloat(floats)
+		default:
+			skipped++
+			r.Skip()
+		}
+	}
+
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if u != 300 || s != "hello" || d != 48.390394 || f != 1.5 || !b || n != "nested" || skipped != 1 {
+		t.Fatalf("unexpected values %d %q %f %f %t %q %d", u, s, d, f, b, n, skipped)
+	}
+
+	if len(uints) != 4 || uints[2] != 70000 || uints[3] != 5 || len(floats) != 2 || floats[1] != 2 {
+		t.Fatalf("unexpected repeated values %v %v", uints, floats)
+	}
+}
+
+func TestReaderMalformed(t *testing.T) {
+	data := pbftest.AppendString(nil, 1, "hello")
+
+	// Truncated length delimited value
+	r := pbf.NewReader(data[:len(data)-1])
+	for r.Next() {
+		r.Skip()
+	}
+
+	if r.Err() != pbf.ErrMalformed {
+		t.Fatalf("expected malformed error, got %v", r.Err())
+	}
+
+	// Wire type mismatch
+	r = pbf.NewReader(data)
+	for r.Next() {
+		r.Uint64()
+	}
+
+	if r.Err() != pbf.ErrMalformed {
+		t.Fatalf("expected malformed error, got %v", r.Err())
+	}
+}
+
+func TestReaderMessageError(t *testing.T) {
+	nested := pbftest.AppendString(nil, 1, "nested")
+	data := pbftest.AppendBytes(nil, 1, nested[:len(nested)-1])
+
+	r := pbf.NewReader(data)
+	for r.Next() {
+		m := r.Message()
+		for m.Next() {
+			m.Skip()
+		}
+	}
+
+	if r.Err() != pbf.ErrMalformed {
+		t.Fatalf("expected embedded message error to be reported, got %v", r.Err())
+	}
+}
@@ -0,0 +1,173 @@
+// Package polyline encodes and decodes polylines using the Google polyline algorithm,
+// as used by valhalla for shapes (with 6 digits precision by default).
+// See https://valhalla.github.io/valhalla/decoding/
+package polyline
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+const (
+	// Precision5 precision of polyline5 encoded shapes
+	Precision5 = 5
+
+	// Precision6 precision of polyline6 encoded shapes, used by valhalla by default
+	Precision6 = 6
+)
+
+// ErrMalformed is returned when decoding an invalid polyline
+var ErrMalformed = errors.New("malformed polyline")
+
+// Point is a coordinate of a polyline, in degrees
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// factor returns the multiplication factor for given precision
+func factor(precision int) float64 {
+	return math.Pow10(precision)
+}
+
+// Encode encodes points to a polyline with given precision (number of decimal digits)
+func Encode(points []Point, precision int) string {
+	f := factor(precision)
+	sb := strings.Builder{}
+	sb.Grow(len(points) * 8)
+
+	var prevLat, prevLon int64
+	for _, point := range points {
+		lat := int64(math.Round(point.Lat * f))
+		lon := int64(math.Round(point.Lon * f))
+
+		encodeValue(&sb, lat-prevLat)
+		encodeValue(&sb, lon-prevLon)
+
+		prevLat, prevLon = lat, lon
+	}
+
+	return sb.String()
+}
+
+// encodeValue appends the encoded signed value to sb
+func encodeValue(sb *strings.Builder, value int64) {
+	uvalue := uint64(value) << 1
+	if value < 0 {
+		uvalue = ^uvalue
+	}
+
+	for uvalue >= 0x20 {
+		sb.WriteByte(byte((0x20 | (uvalue & 0x1f)) + 63))
+		uvalue >>= 5
+	}
+
+	sb.WriteByte(byte(uvalue + 63))
+}
+
+// Decode decodes polyline encoded with given precision (number of decimal digits)
+func Decode(encoded string, precision int) ([]Point, error) {
+	points := make([]Point, 0, len(encoded)/4)
+
+	decoder := NewDecoder(encoded, precision)
+	for decoder.Next() {
+		points = append(points, decoder.Point())
+	}
+
+	if err := decoder.Err(); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+// Decoder decodes a polyline point by point, without allocating
+//
+//	decoder := polyline.NewDecoder(encoded, polyline.Precision6)
+//	for decoder.Next() {
+//		point := decoder.Point()
+//	}
+//
+//	if err := decoder.Err(); err != nil { ... }
+type Decoder struct {
+	encoded  string
+	pos      int
+	factor   float64
+	lat, lon int64
+	err      error
+}
+
+// NewDecoder returns a decoder for polyline encoded with given precision
+func NewDecoder(encoded string, precision int) *Decoder {
+	return &Decoder{encoded: encoded, factor: factor(precision)}
+}
+
+// Next decodes the next point, returns false at the end of the polyline or on error
+func (decoder *Decoder) Next() bool {
+	if decoder.err != nil || decoder.pos >= len(decoder.encoded) {
+		return false
+	}
+
+	dlat, ok := decoder.decodeValue()
+	if !ok {
+		return false
+	}
+
+	dlon, ok := decoder.decodeValue()
+	if !ok {
+		return false
+	}
+
+	decoder.lat += dlat
+	decoder.lon += dlon
+
+	return true
+}
+
+// Point returns the last decoded point
+func (decoder *Decoder) Point() Point {
+	return Point{
+		Lat: float64(decoder.lat) / decoder.factor,
+		Lon: float64(decoder.lon) / decoder.factor,
+	}
+}
+
+// Err returns the error encountered while decoding, if any
+func (decoder *Decoder) Err() error {
+	return decoder.err
+}
+
+// decodeValue decodes the next signed value
+func (decoder *Decoder) decodeValue() (int64, bool) {
+	var result uint64
+	var shift uint
+
+	for {
+		if decoder.pos >= len(decoder.encoded) || shift > 63 {
+			decoder.err = ErrMalformed
+			return 0, false
+		}
+
+		b := uint64(decoder.encoded[decoder.pos]) - 63
+		decoder.pos++
+
+		if b > 0x3f {
+			decoder.err = ErrMalformed
+			return 0, false
+		}
+
+		result |= (b & 0x1f) << shift
+		shift += 5
+
+		if b < 0x20 {
+			break
+		}
+	}
+
+	if result&1 != 0 {
+		return ^int64(result >> 1), true
+	}
+
+	return int64(result >> 1), true
+}
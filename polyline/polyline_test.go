@@ -0,0 +1,70 @@
+package polyline
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	// Example from the google polyline algorithm documentation
+	points := []Point{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	encoded := "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+
+	if got := Encode(points, Precision5); got != encoded {
+		t.Fatalf("expected %q, got %q", encoded, got)
+	}
+
+	decoded, err := Decode(encoded, Precision5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded) != len(points) {
+		t.Fatalf("expected %d points, got %d", len(points), len(decoded))
+	}
+
+	for i := range points {
+		if math.Abs(decoded[i].Lat-points[i].Lat) > 1e-5 || math.Abs(decoded[i].Lon-points[i].Lon) > 1e-5 {
+			t.Fatalf("point %d: expected %v, got %v", i, points[i], decoded[i])
+		}
+	}
+}
+
+func TestEncodeDecodePrecisions(t *testing.T) {
+	points := []Point{{48.390394, -4.486076}, {48.45252, -4.25252}, {-33.8688197, 151.2092955}}
+
+	for _, precision := range []int{Precision5, Precision6, 7} {
+		decoded, err := Decode(Encode(points, precision), precision)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tolerance := math.Pow10(-precision)
+		for i := range points {
+			if math.Abs(decoded[i].Lat-points[i].Lat) > tolerance || math.Abs(decoded[i].Lon-points[i].Lon) > tolerance {
+				t.Fatalf("precision %d, point %d: expected %v, got %v", precision, i, points[i], decoded[i])
+			}
+		}
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	if _, err := Decode("_p~iF~ps|U_ulL", Precision5); err != ErrMalformed {
+		t.Fatalf("expected malformed error, got %v", err)
+	}
+}
+
+func TestDecoderAllocs(t *testing.T) {
+	encoded := Encode([]Point{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}, Precision6)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		decoder := NewDecoder(encoded, Precision6)
+		for decoder.Next() {
+			_ = decoder.Point()
+		}
+	})
+
+	if allocs > 0 {
+		t.Fatalf("expected decoder not to allocate, got %.0f allocs", allocs)
+	}
+}
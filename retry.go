@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	defaultRetryBaseBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff  = 5 * time.Second
+)
+
+// DefaultRetryableStatusCodes http status codes retried when RetryPolicy.RetryableStatusCodes is nil
+var DefaultRetryableStatusCodes = []int{
+	fasthttp.StatusTooManyRequests,
+	fasthttp.StatusBadGateway,
+	fasthttp.StatusServiceUnavailable,
+	fasthttp.StatusGatewayTimeout,
+}
+
+// RetryPolicy configures how failed requests are retried.
+// Failed attempts are retried with an exponential backoff: the delay before the
+// nth retry is BaseBackoff * 2^(n-1), bounded by MaxBackoff and randomized by Jitter.
+type RetryPolicy struct {
+	// MaxAttempts maximum number of attempts, including the first one.
+	// Values lower than 2 disable retries.
+	MaxAttempts int `json:"max_attempts" yaml:"max_attempts"`
+
+	// BaseBackoff delay before the first retry. Default 100ms.
+	BaseBackoff time.Duration `json:"base_backoff" yaml:"base_backoff"`
+
+	// MaxBackoff maximum delay between two attempts. Default 5s.
+	MaxBackoff time.Duration `json:"max_backoff" yaml:"max_backoff"`
+
+	// Jitter fraction of the delay which is randomized, from 0 (no randomization)
+	// to 1 (delay picked between 0 and the computed backoff).
+	Jitter float64 `json:"jitter" yaml:"jitter"`
+
+	// RetryableStatusCodes http status codes of responses to retry.
+	// DefaultRetryableStatusCodes are used if nil.
+	RetryableStatusCodes []int `json:"retryable_status_codes" yaml:"retryable_status_codes"`
+
+	// RetryableErrorCodes valhalla error codes (error_code in error responses) to retry,
+	// regardless of the response status code.
+	RetryableErrorCodes []ErrorCode `json:"retryable_error_codes" yaml:"retryable_error_codes"`
+
+	// AssumeIdempotent allows to retry requests failing with a transport error after
+	// they may have reached the server (ie: connection reset, read timeout).
+	// Valhalla actions do not modify any server state, so this is usually safe.
+	// When false, only transport errors raised before sending the request
+	// (ie: dial errors) are retried.
+	AssumeIdempotent bool `json:"assume_idempotent" yaml:"assume_idempotent"`
+}
+
+// DefaultRetryPolicy returns a policy making up to 3 attempts for transport errors
+// and DefaultRetryableStatusCodes
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:      3,
+		BaseBackoff:      defaultRetryBaseBackoff,
+		MaxBackoff:       defaultRetryMaxBackoff,
+		Jitter:           0.5,
+		AssumeIdempotent: true,
+	}
+}
+
+// retryable returns true if a request failed with err should be retried.
+// transport is true if err comes from the http layer.
+func (policy *RetryPolicy) retryable(err error, transport bool) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if transport {
+		return policy.AssumeIdempotent || !requestSent(err)
+	}
+
+	errRes := &ErrorResponse{}
+	if !errors.As(err, &errRes) {
+		return false
+	}
+
+	statusCodes := policy.RetryableStatusCodes
+	if statusCodes == nil {
+		statusCodes = DefaultRetryableStatusCodes
+	}
+
+	for _, code := range statusCodes {
+		if code == errRes.StatusCode {
+			return true
+		}
+	}
+
+	for _, code := range policy.RetryableErrorCodes {
+		if code == errRes.ErrorCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff returns the delay to wait after given attempt
+func (policy *RetryPolicy) backoff(attempt int) time.Duration {
+	base := policy.BaseBackoff
+	if base <= 0 {
+		base = defaultRetryBaseBackoff
+	}
+
+	max := policy.MaxBackoff
+	if max <= 0 {
+		max = defaultRetryMaxBackoff
+	}
+
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		delay = max
+	}
+
+	if policy.Jitter > 0 {
+		jitter := policy.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay)) //nolint:gosec
+	}
+
+	return delay
+}
+
+// wait for the backoff delay of given attempt, or for min if longer.
+// Returns ctx error if ctx is done before.
+func (policy *RetryPolicy) wait(ctx context.Context, attempt int, min time.Duration) error {
+	delay := policy.backoff(attempt)
+	if delay < min {
+		delay = min
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// requestSent returns false if transport error err was raised before the request was sent
+func requestSent(err error) bool {
+	if errors.Is(err, fasthttp.ErrNoFreeConns) || errors.Is(err, fasthttp.ErrDialTimeout) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+
+	opErr := &net.OpError{}
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{BaseBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+
+	expected := []time.Duration{10, 20, 40, 50, 50}
+	for i, exp := range expected {
+		if delay := policy.backoff(i + 1); delay != exp*time.Millisecond {
+			t.Fatalf("attempt %d: expected %s backoff, got %s", i+1, exp*time.Millisecond, delay)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if delay := policy.backoff(2); delay < 10*time.Millisecond || delay > 20*time.Millisecond {
+			t.Fatalf("jittered backoff out of bounds: %s", delay)
+		}
+	}
+}
+
+func TestRetryPolicyStatusCode(t *testing.T) {
+	calls := int32(0)
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+			return
+		}
+
+		ctx.SetBodyString(`{"trip": {}}`)
+	})
+
+	clt.config.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond}
+
+	if _, err := clt.Route(&RouteInput{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if atomic.LoadInt32(&calls) != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryPolicyErrorCode(t *testing.T) {
+	calls := int32(0)
+	clt := getLocalTestClient(t, func(ctx *fasthttp.RequestCtx) {
+		atomic.AddInt32(&calls, 1)
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetBodyString(`{"error_code": 171, "error": "No suitable edges near location", "status_code": 400, "status": "Bad Request"}`)
+	})
+
+	clt.config.RetryPolicy = &RetryPolicy{MaxAttempts: 4, BaseBackoff: time.Millisecond}
+
+	_, err := clt.Route(&RouteInput{})
+
+	retryErr := &RetryError{}
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 1 {
+		t.Fatalf("expected error after a single attempt, got %v", err)
+	}
+
+	errRes := &ErrorResponse{}
+	if !errors.As(err, &errRes) || errRes.ErrorCode != 171 {
+		t.Fatalf("expected error response with code 171, got %v", err)
+	}
+
+	clt.config.RetryPolicy.RetryableErrorCodes = []ErrorCode{ErrNoSuitableEdges}
+	atomic.StoreInt32(&calls, 0)
+
+	_, err = clt.Route(&RouteInput{})
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 4 || atomic.LoadInt32(&calls) != 4 {
+		t.Fatalf("expected error after 4 attempts, got %v (%d calls)", err, calls)
+	}
+}
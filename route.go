@@ -1,10 +1,10 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/goccy/go-json"
-	"github.com/valyala/fasthttp"
+	"github.com/angelodlfrtr/valhalla-http-client-go/polyline"
 )
 
 const (
@@ -34,6 +34,14 @@ const (
 	DirectionsTypeInstructions string = "instructions"
 )
 
+// Response formats of the route action
+const (
+	FormatJSON string = "json"
+	FormatOSRM string = "osrm"
+	FormatGPX  string = "gpx"
+	FormatPBF  string = "pbf"
+)
+
 // RouteInputLocationSearchFilter search filter for route input location.
 type RouteInputLocationSearchFilter struct {
 	// ExcludeTunnel whether to exclude roads marked as tunnels.
@@ -215,33 +223,27 @@ type RouteLocation struct {
 
 	// DateTime (response only) Expected date/time for the user to be at the location
 	// using the ISO 8601 format (YYYY-MM-DDThh:mm) in the local time zone of departure or arrival.
-	// For example "2015-12-29T08:00".
+	// For example "2015-12-29T08:00". See ArrivalTime.
 	DateTime *string `json:"date_time,omitempty"`
 
+	// TimeZoneOffset (response only) the time zone offset at the location, when date_time is set.
+	// For example "+01:00".
+	TimeZoneOffset *string `json:"time_zone_offset,omitempty"`
+
+	// TimeZoneName (response only) the time zone name at the location, when date_time is set.
+	// For example "Europe/Paris".
+	TimeZoneName *string `json:"time_zone_name,omitempty"`
+
 	// Output only fields
 
 	// OriginalIndex returned in output
 	OriginalIndex *int `json:"original_index,omitempty"`
 }
 
-type RouteInputDateTime struct {
-	// Type of date time.
-	//
-	// 0 - Current departure time.
-	//
-	// 1 - Specified departure time
-	//
-	// 2 - Specified arrival time. Not yet implemented for multimodal costing method.
-	//
-	// 3 - Invariant specified time. Time does not vary over the course of the path.
-	// Not implemented for multimodal or bike share routing
-	Type *int `json:"type,omitempty"`
-
-	// Value the date and time is specified in ISO 8601 format (YYYY-MM-DDThh:mm)
-	// in the local time zone of departure or arrival.
-	// For example "2016-07-03T08:06"
-	Value *string `json:"value,omitempty"`
-}
+// RouteInputDateTime date time of route and matrix inputs.
+//
+// Deprecated: use DateTime.
+type RouteInputDateTime = DateTime
 
 // RouteInput is the input for turn by turn routing service
 type RouteInput struct {
@@ -310,12 +312,20 @@ type RouteInput struct {
 	ExcludePolygons [][][]float64 `json:"exclude_polygons,omitempty"`
 
 	// DateTime this is the local date and time at the location.
-	DateTime *RouteInputDateTime `json:"date_time,omitempty"`
+	// See DepartAt, ArriveBy, Now and Invariant.
+	DateTime *DateTime `json:"date_time,omitempty"`
 
 	// OutFormat if no out_format is specified, JSON is returned.
 	// Future work includes PBF (protocol buffer) support.
 	OutFormat *string `json:"out_format,omitempty"`
 
+	// Format response format: json (default) or pbf.
+	// Route decodes json and pbf responses into the same output, pbf being faster to decode
+	// for large trips (maneuver signs and transit info are not decoded from pbf).
+	// Use RouteOSRM to get routes in the OSRM format, and RouteOutput.WriteGPX to export
+	// routes as GPX: Route and OptimizedRoute fail with a ValidationError for osrm and gpx.
+	Format *string `json:"format,omitempty"`
+
 	// ID name your route request. If id is specified, the naming will be sent thru to the response.
 	ID *string `json:"id,omitempty"`
 
@@ -399,7 +409,7 @@ type RouteOutputManeuverTransitInfoTransitStop struct {
 
 type RouteOutputManeuverTransitInfo struct {
 	// Global transit route identifier from Transitland.
-	OnestopId *string `json:"OnestopId,omitempty"`
+	OnestopId *string `json:"onestop_id,omitempty"`
 
 	// Short name describing the transit route. For example "N".
 	ShortName *string `json:"short_name,omitempty"`
@@ -412,11 +422,11 @@ type RouteOutputManeuverTransitInfo struct {
 	Headsign *string `json:"headsign,omitempty"`
 
 	// The numeric color value associated with a transit route.
-	// The value for yellow would be "16567306".
-	Color *string `json:"color,omitempty"`
+	// The value for yellow would be 16567306.
+	Color *int `json:"color,omitempty"`
 
-	// The numeric text color value associated with a transit route. The value for black would be "0".
-	TextColor *string `json:"text_color,omitempty"`
+	// The numeric text color value associated with a transit route. The value for black would be 0.
+	TextColor *int `json:"text_color,omitempty"`
 
 	// The description of the the transit route. For example "Trains operate from Ditmars Boulevard,
 	// Queens, to Stillwell Avenue, Brooklyn, at all times. N trains in Manhattan operate along
@@ -441,9 +451,8 @@ type RouteOutputManeuverTransitInfo struct {
 }
 
 type RouteOutputManeuver struct {
-	// Type of maneuver. See doc for a list.
-	// https://valhalla.readthedocs.io/en/latest/api/turn-by-turn/api-reference/
-	Type *int `json:"type,omitempty"`
+	// Type of maneuver.
+	Type *ManeuverType `json:"type,omitempty"`
 
 	// Instruction written maneuver instruction.
 	// Describes the maneuver, such as "Turn right onto Main Street".
@@ -523,19 +532,19 @@ type RouteOutputManeuver struct {
 	// Typically used with a transit maneuver, such as "Arrive at 8:10 AM at 34 St - Herald Sq".
 	VerbalArriveInstruction *string `json:"verbal_arrive_instruction,omitempty"`
 
-	// TODO
-	TransitInfo interface{} `json:"transit_info,omitempty"`
+	// TransitInfo the transit route and stops of transit maneuvers.
+	TransitInfo *RouteOutputManeuverTransitInfo `json:"transit_info,omitempty"`
 
 	// VerbalMultiCue true if the verbal_pre_transition_instruction has been appended
 	// with the verbal instruction of the next maneuver.
 	VerbalMultiCue *bool `json:"verbal_multi_cue,omitempty"`
 
 	// TravelMode travel mode of the maneuver: drive, pedestrian, bicycle, transit
-	TravelMode *string `json:"travel_mode,omitempty"`
+	TravelMode *TravelMode `json:"travel_mode,omitempty"`
 
-	// TravelType travel type
-	// Possibles: car, foot, road, tram, metro, rail, bus, ferry, cable_car, gondola, funicular
-	TravelType *string `json:"travel_type,omitempty"`
+	// TravelType travel type, depending on the travel mode
+	// (ie: car, foot, road, tram, metro, rail, bus, ferry, cable_car, gondola, funicular)
+	TravelType *TravelType `json:"travel_type,omitempty"`
 }
 
 type RouteOutputLeg struct {
@@ -549,6 +558,15 @@ type RouteOutputLeg struct {
 	Shape *string `json:"shape,omitempty"`
 }
 
+// DecodedShape returns the decoded points of the leg shape
+func (leg *RouteOutputLeg) DecodedShape() ([]polyline.Point, error) {
+	if leg.Shape == nil {
+		return nil, nil
+	}
+
+	return polyline.Decode(*leg.Shape, polyline.Precision6)
+}
+
 type RouteOutputTrip struct {
 	// Locations the locations used to generate the route.
 	Locations []*RouteLocation `json:"locations,omitempty"`
@@ -570,34 +588,18 @@ type RouteOutput struct {
 
 // Route returns the route between the given locations.
 func (client *Client) Route(input *RouteInput) (*RouteOutput, error) {
-	req, err := client.buildBaseRequest(fasthttp.MethodPost, "/route", input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build request for route: %w", err)
-	}
-	defer fasthttp.ReleaseRequest(req)
-
-	// Acquire response
-	resp := fasthttp.AcquireResponse()
-	defer fasthttp.ReleaseResponse(resp)
-
-	if err := client.httpClient.Do(req, resp); err != nil {
-		return nil, fmt.Errorf("error while calling http route service: %w", err)
-	}
-
-	if resp.StatusCode() != fasthttp.StatusOK {
-		errRes := &ErrorResponse{}
-		if err := json.Unmarshal(resp.Body(), errRes); err != nil {
-			errRes.StatusCode = resp.StatusCode()
-			errRes.ErrorMessage = string(resp.Body())
-		}
+	return client.RouteContext(context.Background(), input)
+}
 
-		return nil, errRes
+// RouteContext is like Route, the request is cancelled when ctx is done.
+func (client *Client) RouteContext(ctx context.Context, input *RouteInput) (*RouteOutput, error) {
+	if err := input.validateOutputFormat(); err != nil {
+		return nil, fmt.Errorf("%s: %w", ActionRoute, err)
 	}
 
-	// Extract response
 	output := &RouteOutput{}
-	if err := json.Unmarshal(resp.Body(), output); err != nil {
-		return nil, fmt.Errorf("error while decoding http route json response data: %w", err)
+	if err := client.call(ctx, ActionRoute, input, output); err != nil {
+		return nil, err
 	}
 
 	return output, nil
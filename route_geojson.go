@@ -0,0 +1,172 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/angelodlfrtr/valhalla-http-client-go/polyline"
+	"github.com/paulmach/go.geojson"
+)
+
+// Values of the "kind" property of the features built by RouteOutput.ToGeoJSON
+const (
+	RouteGeoJSONKindLeg      string = "leg"
+	RouteGeoJSONKindManeuver string = "maneuver"
+	RouteGeoJSONKindLocation string = "location"
+)
+
+// RouteGeoJSONOptions options of RouteOutput.ToGeoJSON
+type RouteGeoJSONOptions struct {
+	// IncludeManeuvers adds one feature per maneuver, sliced from the leg shape.
+	// Maneuvers with a single shape point (ie: arrivals) are Point features.
+	IncludeManeuvers bool
+
+	// ExcludeLocations removes the Point features of the trip locations.
+	ExcludeLocations bool
+}
+
+// ToGeoJSON converts the route to a feature collection, containing one LineString
+// feature per leg and one Point feature per trip location. Features have a "kind"
+// property set to leg, maneuver or location. opts may be nil.
+func (output *RouteOutput) ToGeoJSON(opts *RouteGeoJSONOptions) (*geojson.FeatureCollection, error) {
+	if opts == nil {
+		opts = &RouteGeoJSONOptions{}
+	}
+
+	fc := geojson.NewFeatureCollection()
+	if output.Trip == nil {
+		return fc, nil
+	}
+
+	for legIndex, leg := range output.Trip.Legs {
+		shape, err := leg.DecodedShape()
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode shape of leg %d: %w", legIndex, err)
+		}
+
+		coords := pointsToCoordinates(shape)
+
+		legFeature := geojson.NewLineStringFeature(coords)
+		legFeature.SetProperty("kind", RouteGeoJSONKindLeg)
+		legFeature.SetProperty("leg_index", legIndex)
+		setSummaryProperties(legFeature, leg.Summary)
+		fc.AddFeature(legFeature)
+
+		if !opts.IncludeManeuvers {
+			continue
+		}
+
+		for maneuverIndex, maneuver := range leg.Maneuvers {
+			feature := maneuverFeature(maneuver, coords)
+			if feature == nil {
+				continue
+			}
+
+			feature.SetProperty("kind", RouteGeoJSONKindManeuver)
+			feature.SetProperty("leg_index", legIndex)
+			feature.SetProperty("maneuver_index", maneuverIndex)
+			fc.AddFeature(feature)
+		}
+	}
+
+	if opts.ExcludeLocations {
+		return fc, nil
+	}
+
+	for index, location := range output.Trip.Locations {
+		if location == nil || location.Lat == nil || location.Lon == nil {
+			continue
+		}
+
+		feature := geojson.NewPointFeature([]float64{*location.Lon, *location.Lat})
+		feature.SetProperty("kind", RouteGeoJSONKindLocation)
+		feature.SetProperty("location_index", index)
+		setStringProperty(feature, "type", location.Type)
+		setStringProperty(feature, "name", location.Name)
+		setStringProperty(feature, "side_of_street", location.SideOfStreet)
+
+		if location.OriginalIndex != nil {
+			feature.SetProperty("original_index", *location.OriginalIndex)
+		}
+
+		fc.AddFeature(feature)
+	}
+
+	return fc, nil
+}
+
+// maneuverFeature returns the feature of maneuver sliced from the leg coords,
+// nil if the maneuver shape indexes are out of the leg shape
+func maneuverFeature(maneuver *RouteOutputManeuver, coords [][]float64) *geojson.Feature {
+	if maneuver == nil || maneuver.BeginShapeIndex == nil || maneuver.EndShapeIndex == nil {
+		return nil
+	}
+
+	begin, end := *maneuver.BeginShapeIndex, *maneuver.EndShapeIndex
+	if begin < 0 || end < begin || end >= len(coords) {
+		return nil
+	}
+
+	var feature *geojson.Feature
+	if begin == end {
+		feature = geojson.NewPointFeature(coords[begin])
+	} else {
+		feature = geojson.NewLineStringFeature(coords[begin : end+1])
+	}
+
+	if maneuver.Type != nil {
+		feature.SetProperty("maneuver_type", int(*maneuver.Type))
+	}
+
+	setStringProperty(feature, "instruction", maneuver.Instruction)
+	setFloatProperty(feature, "time", maneuver.Time)
+	setFloatProperty(feature, "length", maneuver.Length)
+	setBoolProperty(feature, "toll", maneuver.Toll)
+	setBoolProperty(feature, "ferry", maneuver.Ferry)
+	setBoolProperty(feature, "rough", maneuver.Rough)
+	setBoolProperty(feature, "gate", maneuver.Gate)
+
+	if len(maneuver.StreetNames) > 0 {
+		feature.SetProperty("street_names", maneuver.StreetNames)
+	}
+
+	return feature
+}
+
+// setSummaryProperties sets the time, length and cost of summary as feature properties
+func setSummaryProperties(feature *geojson.Feature, summary *RouteOutputTripSummary) {
+	if summary == nil {
+		return
+	}
+
+	setFloatProperty(feature, "time", summary.Time)
+	setFloatProperty(feature, "length", summary.Length)
+	setFloatProperty(feature, "cost", summary.Cost)
+}
+
+func setStringProperty(feature *geojson.Feature, key string, value *string) {
+	if value != nil {
+		feature.SetProperty(key, *value)
+	}
+}
+
+func setFloatProperty(feature *geojson.Feature, key string, value *float64) {
+	if value != nil {
+		feature.SetProperty(key, *value)
+	}
+}
+
+func setBoolProperty(feature *geojson.Feature, key string, value *bool) {
+	if value != nil {
+		feature.SetProperty(key, *value)
+	}
+}
+
+// pointsToCoordinates converts points to geojson [lon, lat] coordinates
+func pointsToCoordinates(points []polyline.Point) [][]float64 {
+	coords := make([][]float64, 0, len(points))
+	for _, point := range points {
+		coords = append(coords, []float64{point.Lon, point.Lat})
+	}
+
+	return coords
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/angelodlfrtr/valhalla-http-client-go/polyline"
+	"github.com/gotidy/ptr"
+)
+
+func getTestRouteOutput() *RouteOutput {
+	shape := []polyline.Point{
+		{Lat: 48.390394, Lon: -4.486076},
+		{Lat: 48.40912, Lon: -4.39826},
+		{Lat: 48.45252, Lon: -4.25252},
+	}
+
+	return &RouteOutput{
+		Trip: &RouteOutputTrip{
+			Locations: []*RouteLocation{
+				{Lat: ptr.Float64(48.390394), Lon: ptr.Float64(-4.486076), Type: ptr.String(RouteInputLocationTypeBreak), OriginalIndex: ptr.Int(0)},
+				{Lat: ptr.Float64(48.45252), Lon: ptr.Float64(-4.25252), Type: ptr.String(RouteInputLocationTypeBreak), OriginalIndex: ptr.Int(1)},
+			},
+			Legs: []*RouteOutputLeg{{
+				Shape:   ptr.String(polyline.Encode(shape, polyline.Precision6)),
+				Summary: &RouteOutputTripSummary{Time: ptr.Float64(900), Length: ptr.Float64(19.2)},
+				Maneuvers: []*RouteOutputManeuver{
+					{
+						Type:            maneuverTypePtr(ManeuverTypeStart),
+						Instruction:     ptr.String("Drive east."),
+						Time:            ptr.Float64(900),
+						Length:          ptr.Float64(19.2),
+						Toll:            ptr.Bool(true),
+						BeginShapeIndex: ptr.Int(0),
+						EndShapeIndex:   ptr.Int(2),
+					},
+					{
+						Type:            maneuverTypePtr(ManeuverTypeDestination),
+						Instruction:     ptr.String("You have arrived at your destination."),
+						BeginShapeIndex: ptr.Int(2),
+						EndShapeIndex:   ptr.Int(2),
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestRouteOutputToGeoJSON(t *testing.T) {
+	output := getTestRouteOutput()
+
+	fc, err := output.ToGeoJSON(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fc.Features) != 3 {
+		t.Fatalf("expected 1 leg and 2 locations features, got %d features", len(fc.Features))
+	}
+
+	leg := fc.Features[0]
+	if !leg.Geometry.IsLineString() || len(leg.Geometry.LineString) != 3 || leg.Properties["kind"] != RouteGeoJSONKindLeg {
+		t.Fatalf("unexpected leg feature %+v", leg)
+	}
+
+	if lon := leg.Geometry.LineString[0][0]; lon != -4.486076 {
+		t.Fatalf("expected coordinates as [lon, lat], got %v", leg.Geometry.LineString[0])
+	}
+
+	fc, err = output.ToGeoJSON(&RouteGeoJSONOptions{IncludeManeuvers: true, ExcludeLocations: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fc.Features) != 3 {
+		t.Fatalf("expected 1 leg and 2 maneuvers features, got %d features", len(fc.Features))
+	}
+
+	depart, arrive := fc.Features[1], fc.Features[2]
+	if !depart.Geometry.IsLineString() || depart.Properties["instruction"] != "Drive east." || depart.Properties["toll"] != true {
+		t.Fatalf("unexpected depart maneuver feature %+v", depart)
+	}
+
+	if !arrive.Geometry.IsPoint() || arrive.Properties["maneuver_index"] != 1 {
+		t.Fatalf("unexpected arrive maneuver feature %+v", arrive)
+	}
+}
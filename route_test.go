@@ -3,6 +3,7 @@ package client
 import (
 	"testing"
 
+	"github.com/angelodlfrtr/valhalla-http-client-go/polyline"
 	"github.com/gotidy/ptr"
 )
 
@@ -25,3 +26,17 @@ func TestRoute(t *testing.T) {
 
 	t.Log(output)
 }
+
+func TestRouteOutputLegDecodedShape(t *testing.T) {
+	points := []polyline.Point{{Lat: 48.390394, Lon: -4.486076}, {Lat: 48.45252, Lon: -4.25252}}
+	leg := &RouteOutputLeg{Shape: ptr.String(polyline.Encode(points, polyline.Precision6))}
+
+	decoded, err := leg.DecodedShape()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded) != len(points) || decoded[1] != points[1] {
+		t.Fatalf("expected %v, got %v", points, decoded)
+	}
+}
@@ -0,0 +1,186 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// ThrottleConfig shapes the traffic sent to an endpoint.
+// Requests wait for the limits to allow them, or for their context to be done.
+type ThrottleConfig struct {
+	// RequestsPerSecond (optional) maximum rate of requests, as a token bucket. Unlimited if 0.
+	RequestsPerSecond float64 `json:"requests_per_second" yaml:"requests_per_second"`
+
+	// Burst size of the token bucket: number of requests which can be sent at once
+	// after an idle period. Default 1.
+	Burst int `json:"burst" yaml:"burst"`
+
+	// MaxInFlight (optional) maximum number of concurrent requests. Unlimited if 0.
+	MaxInFlight int `json:"max_in_flight" yaml:"max_in_flight"`
+}
+
+// endpointThrottle limits the requests of an endpoint, and pauses them on 429 responses
+type endpointThrottle struct {
+	slots chan struct{}
+
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// newEndpointThrottle returns the throttle of an endpoint configured by cfg (unlimited if nil)
+func newEndpointThrottle(cfg *ThrottleConfig) *endpointThrottle {
+	throttle := &endpointThrottle{}
+	if cfg == nil {
+		return throttle
+	}
+
+	if cfg.MaxInFlight > 0 {
+		throttle.slots = make(chan struct{}, cfg.MaxInFlight)
+	}
+
+	if cfg.RequestsPerSecond > 0 {
+		throttle.rate = cfg.RequestsPerSecond
+		throttle.burst = float64(cfg.Burst)
+		if throttle.burst < 1 {
+			throttle.burst = 1
+		}
+
+		throttle.tokens = throttle.burst
+		throttle.last = time.Now()
+	}
+
+	return throttle
+}
+
+// acquire waits until a request can be sent, returns a function releasing its in flight slot.
+// Fails with ctx error if ctx is done before, or with context.DeadlineExceeded if
+// the ctx deadline is before the request can be sent.
+func (throttle *endpointThrottle) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if throttle.slots != nil {
+		select {
+		case throttle.slots <- struct{}{}:
+			release = func() { <-throttle.slots }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	now := time.Now()
+	delay := throttle.reserve(now)
+	if delay <= 0 {
+		return release, nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(delay)) {
+		throttle.cancel()
+		release()
+
+		return nil, context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return release, nil
+	case <-ctx.Done():
+		throttle.cancel()
+		release()
+
+		return nil, ctx.Err()
+	}
+}
+
+// reserve takes a token and returns the delay before the request can be sent
+func (throttle *endpointThrottle) reserve(now time.Time) time.Duration {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+
+	delay := throttle.pausedUntil.Sub(now)
+
+	if throttle.rate > 0 {
+		throttle.tokens += now.Sub(throttle.last).Seconds() * throttle.rate
+		if throttle.tokens > throttle.burst {
+			throttle.tokens = throttle.burst
+		}
+
+		throttle.last = now
+		throttle.tokens--
+
+		if throttle.tokens < 0 {
+			if wait := time.Duration(-throttle.tokens / throttle.rate * float64(time.Second)); wait > delay {
+				delay = wait
+			}
+		}
+	}
+
+	return delay
+}
+
+// cancel gives back the token of a reservation whose request is not sent
+func (throttle *endpointThrottle) cancel() {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+
+	if throttle.rate > 0 {
+		throttle.tokens++
+	}
+}
+
+// pause delays the requests until d from now
+func (throttle *endpointThrottle) pause(d time.Duration) {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(throttle.pausedUntil) {
+		throttle.pausedUntil = until
+	}
+}
+
+// parseRetryAfter parses the value of a Retry-After header, in seconds or as an http date.
+// Returns 0 if the header is missing or invalid.
+func parseRetryAfter(value []byte) time.Duration {
+	if len(value) == 0 {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(string(value)); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+
+		return time.Duration(seconds) * time.Second
+	}
+
+	date, err := fasthttp.ParseHTTPDate(value)
+	if err != nil {
+		return 0
+	}
+
+	if delay := time.Until(date); delay > 0 {
+		return delay
+	}
+
+	return 0
+}
+
+// retryAfter returns the Retry-After delay of the error response of err, 0 if none
+func retryAfter(err error) time.Duration {
+	errRes := &ErrorResponse{}
+	if !errors.As(err, &errRes) {
+		return 0
+	}
+
+	return errRes.RetryAfter
+}
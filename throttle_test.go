@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestThrottleRate(t *testing.T) {
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{Endpoint: "http://valhalla.local", Throttle: &ThrottleConfig{RequestsPerSecond: 20, Burst: 2}},
+		map[string]fasthttp.RequestHandler{"valhalla.local": func(ctx *fasthttp.RequestCtx) {
+			ctx.SetBodyString(`{}`)
+		}},
+	)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := clt.Route(&RouteInput{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 2 requests of the burst, then 2 requests spaced by 50ms
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("expected requests to be rate limited, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	clt.Route(&RouteInput{}) //nolint:errcheck
+
+	if _, err := clt.RouteContext(ctx, &RouteInput{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded before the request can be sent, got %v", err)
+	}
+}
+
+func TestThrottleMaxInFlight(t *testing.T) {
+	inFlight, maxInFlight := int64(0), int64(0)
+
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{Endpoint: "http://valhalla.local", Throttle: &ThrottleConfig{MaxInFlight: 2}},
+		map[string]fasthttp.RequestHandler{"valhalla.local": func(ctx *fasthttp.RequestCtx) {
+			current := atomic.AddInt64(&inFlight, 1)
+			defer atomic.AddInt64(&inFlight, -1)
+
+			for {
+				max := atomic.LoadInt64(&maxInFlight)
+				if current <= max || atomic.CompareAndSwapInt64(&maxInFlight, max, current) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+			ctx.SetBodyString(`{}`)
+		}},
+	)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := clt.Route(&RouteInput{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if maxInFlight != 2 {
+		t.Fatalf("expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+}
+
+func TestThrottleMaxInFlightCancel(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{Endpoint: "http://valhalla.local", Throttle: &ThrottleConfig{MaxInFlight: 1}},
+		map[string]fasthttp.RequestHandler{"valhalla.local": func(ctx *fasthttp.RequestCtx) {
+			<-release
+			ctx.SetBodyString(`{}`)
+		}},
+	)
+
+	go clt.Route(&RouteInput{}) //nolint:errcheck
+
+	for clt.Endpoints()[0].InFlight() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	if _, err := clt.RouteContext(ctx, &RouteInput{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled while waiting, got %v", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	calls := int64(0)
+
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{
+			Endpoint:    "http://valhalla.local",
+			RetryPolicy: &RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond},
+		},
+		map[string]fasthttp.RequestHandler{"valhalla.local": func(ctx *fasthttp.RequestCtx) {
+			if atomic.AddInt64(&calls, 1) == 1 {
+				ctx.Response.Header.Set(fasthttp.HeaderRetryAfter, "1")
+				ctx.SetStatusCode(fasthttp.StatusTooManyRequests)
+
+				return
+			}
+
+			ctx.SetBodyString(`{}`)
+		}},
+	)
+
+	start := time.Now()
+	if _, err := clt.Route(&RouteInput{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second || calls != 2 {
+		t.Fatalf("expected retry after 1s, got %d calls in %s", calls, elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if delay := parseRetryAfter([]byte("120")); delay != 2*time.Minute {
+		t.Fatalf("expected 2m, got %s", delay)
+	}
+
+	date := fasthttp.AppendHTTPDate(nil, time.Now().Add(time.Hour))
+	if delay := parseRetryAfter(date); delay < 59*time.Minute || delay > time.Hour {
+		t.Fatalf("expected about 1h, got %s", delay)
+	}
+
+	for _, value := range []string{"", "-1", "soon"} {
+		if delay := parseRetryAfter([]byte(value)); delay != 0 {
+			t.Fatalf("expected no delay for %q, got %s", value, delay)
+		}
+	}
+}
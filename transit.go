@@ -0,0 +1,179 @@
+package client
+
+import (
+	"time"
+)
+
+// ArrivalTime returns the arrival date time at the stop, in loc (UTC if nil)
+// as stop date times are local to the stop without time zone.
+// Returns a zero time if the stop has no arrival date time.
+func (stop *RouteOutputManeuverTransitInfoTransitStop) ArrivalTime(loc *time.Location) (time.Time, error) {
+	return parseLocalDateTime(stop.ArrivalDateTime, loc)
+}
+
+// DepartureTime returns the departure date time from the stop, in loc (UTC if nil)
+// as stop date times are local to the stop without time zone.
+// Returns a zero time if the stop has no departure date time.
+func (stop *RouteOutputManeuverTransitInfoTransitStop) DepartureTime(loc *time.Location) (time.Time, error) {
+	return parseLocalDateTime(stop.DepartureDateTime, loc)
+}
+
+// LineName returns the name of the transit route: its short name, or its long name,
+// or its onestop id if it has no name
+func (info *RouteOutputManeuverTransitInfo) LineName() string {
+	for _, name := range []*string{info.ShortName, info.LongName, info.OnestopId} {
+		if name != nil && *name != "" {
+			return *name
+		}
+	}
+
+	return ""
+}
+
+// TransitRide a ride on a transit route, from a transit maneuver
+type TransitRide struct {
+	// LegIndex index of the leg of the maneuver
+	LegIndex int
+
+	// ManeuverIndex index of the maneuver in the leg
+	ManeuverIndex int
+
+	// Line the transit route
+	Line *RouteOutputManeuverTransitInfo
+
+	// TravelType type of vehicle (ie: bus, metro), nil if unknown
+	TravelType *TravelType
+
+	// From the boarding stop, nil if unknown
+	From *RouteOutputManeuverTransitInfoTransitStop
+
+	// To the alighting stop, nil if unknown
+	To *RouteOutputManeuverTransitInfoTransitStop
+
+	// Time duration of the ride in seconds
+	Time float64
+
+	// Length length of the ride in the units of the route
+	Length float64
+}
+
+// TransitWalk consecutive pedestrian maneuvers of a transit itinerary
+type TransitWalk struct {
+	// Maneuvers number of maneuvers of the walk
+	Maneuvers int
+
+	// Time duration of the walk in seconds
+	Time float64
+
+	// Length length of the walk in the units of the route
+	Length float64
+}
+
+// TransitItinerary summary of a multimodal route
+type TransitItinerary struct {
+	// Rides transit rides, in order
+	Rides []*TransitRide
+
+	// Walks walking segments, in order
+	Walks []*TransitWalk
+
+	// Transfers number of changes of vehicle. Staying on board while the
+	// vehicle changes route (transit remain on maneuvers) is not a transfer.
+	Transfers int
+}
+
+// Lines returns the line names of the rides, in order
+func (itinerary *TransitItinerary) Lines() []string {
+	lines := make([]string, 0, len(itinerary.Rides))
+	for _, ride := range itinerary.Rides {
+		lines = append(lines, ride.Line.LineName())
+	}
+
+	return lines
+}
+
+// WalkingTime returns the total duration of the walks, in seconds
+func (itinerary *TransitItinerary) WalkingTime() float64 {
+	total := 0.0
+	for _, walk := range itinerary.Walks {
+		total += walk.Time
+	}
+
+	return total
+}
+
+// WalkingLength returns the total length of the walks, in the units of the route
+func (itinerary *TransitItinerary) WalkingLength() float64 {
+	total := 0.0
+	for _, walk := range itinerary.Walks {
+		total += walk.Length
+	}
+
+	return total
+}
+
+// TransitItinerary summarises the transit rides and walks of a multimodal route.
+// Walks are runs of consecutive pedestrian maneuvers, across legs.
+func (output *RouteOutput) TransitItinerary() *TransitItinerary {
+	itinerary := &TransitItinerary{}
+	if output.Trip == nil {
+		return itinerary
+	}
+
+	var walk *TransitWalk
+
+	for legIndex, leg := range output.Trip.Legs {
+		for maneuverIndex, maneuver := range leg.Maneuvers {
+			if maneuver == nil || maneuver.TravelMode == nil {
+				continue
+			}
+
+			switch {
+			case *maneuver.TravelMode == TravelModePedestrian:
+				if walk == nil {
+					walk = &TransitWalk{}
+					itinerary.Walks = append(itinerary.Walks, walk)
+				}
+
+				walk.Maneuvers++
+				walk.Time += floatValue(maneuver.Time)
+				walk.Length += floatValue(maneuver.Length)
+
+				continue
+			case *maneuver.TravelMode == TravelModeTransit && maneuver.TransitInfo != nil:
+				ride := &TransitRide{
+					LegIndex:      legIndex,
+					ManeuverIndex: maneuverIndex,
+					Line:          maneuver.TransitInfo,
+					TravelType:    maneuver.TravelType,
+					Time:          floatValue(maneuver.Time),
+					Length:        floatValue(maneuver.Length),
+				}
+
+				if stops := maneuver.TransitInfo.TransitStops; len(stops) > 0 {
+					ride.From, ride.To = stops[0], stops[len(stops)-1]
+				}
+
+				remainOn := maneuver.Type != nil && *maneuver.Type == ManeuverTypeTransitRemainOn
+				if len(itinerary.Rides) > 0 && !remainOn {
+					itinerary.Transfers++
+				}
+
+				itinerary.Rides = append(itinerary.Rides, ride)
+			}
+
+			walk = nil
+		}
+	}
+
+	return itinerary
+}
+
+// floatValue returns the value of v, 0 if nil
+func floatValue(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+
+	return *v
+}
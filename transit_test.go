@@ -0,0 +1,77 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+const testTransitRouteOutput = `{"trip": {"legs": [{"maneuvers": [
+	{"type": 1, "travel_mode": "pedestrian", "travel_type": "foot", "time": 60, "length": 0.1},
+	{"type": 33, "travel_mode": "pedestrian", "travel_type": "foot", "time": 30, "length": 0.05},
+	{"type": 30, "travel_mode": "transit", "travel_type": "metro", "time": 600, "length": 5.2,
+		"transit_info": {"onestop_id": "r-dr5r-n", "short_name": "N", "color": 16567306, "text_color": 0,
+			"transit_stops": [
+				{"type": 1, "onestop_id": "s-dr5ru7", "name": "14 St - Union Sq", "departure_date_time": "2015-12-29T08:06"},
+				{"type": 1, "name": "Canal St", "arrival_date_time": "2015-12-29T08:16"}
+			]}},
+	{"type": 32, "travel_mode": "transit", "travel_type": "metro", "time": 300, "length": 3,
+		"transit_info": {"long_name": "Broadway Express"}},
+	{"type": 31, "travel_mode": "transit", "travel_type": "bus", "time": 400, "length": 2,
+		"transit_info": {"short_name": "M15"}},
+	{"type": 35, "travel_mode": "pedestrian", "travel_type": "foot", "time": 20, "length": 0.02},
+	{"type": 4, "travel_mode": "pedestrian", "travel_type": "foot"}
+]}]}}`
+
+func TestRouteOutputTransitItinerary(t *testing.T) {
+	output := &RouteOutput{}
+	if err := json.Unmarshal([]byte(testTransitRouteOutput), output); err != nil {
+		t.Fatal(err)
+	}
+
+	info := output.Trip.Legs[0].Maneuvers[2].TransitInfo
+	if *info.OnestopId != "r-dr5r-n" || *info.Color != 16567306 {
+		t.Fatalf("unexpected transit info %+v", info)
+	}
+
+	itinerary := output.TransitItinerary()
+
+	lines := itinerary.Lines()
+	if len(lines) != 3 || lines[0] != "N" || lines[1] != "Broadway Express" || lines[2] != "M15" {
+		t.Fatalf("unexpected lines %v", lines)
+	}
+
+	if itinerary.Transfers != 1 {
+		t.Fatalf("expected 1 transfer, got %d", itinerary.Transfers)
+	}
+
+	if len(itinerary.Walks) != 2 || itinerary.Walks[0].Maneuvers != 2 || itinerary.WalkingTime() != 110 {
+		t.Fatalf("unexpected walks %+v, total time %f", itinerary.Walks, itinerary.WalkingTime())
+	}
+
+	ride := itinerary.Rides[0]
+	if *ride.TravelType != TravelTypeMetro || *ride.From.Name != "14 St - Union Sq" || *ride.To.Name != "Canal St" {
+		t.Fatalf("unexpected ride %+v", ride)
+	}
+
+	loc := time.FixedZone("EST", -5*3600)
+
+	departure, err := ride.From.DepartureTime(loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	arrival, err := ride.To.ArrivalTime(loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !departure.Equal(time.Date(2015, 12, 29, 13, 6, 0, 0, time.UTC)) || arrival.Sub(departure) != 10*time.Minute {
+		t.Fatalf("unexpected stop times %s %s", departure, arrival)
+	}
+
+	if arrival, err := ride.From.ArrivalTime(loc); err != nil || !arrival.IsZero() {
+		t.Fatalf("expected zero arrival time, got %s %v", arrival, err)
+	}
+}
@@ -0,0 +1,424 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidInput is matched by errors.Is for all ValidationError
+var ErrInvalidInput = errors.New("invalid input")
+
+// FieldError an invalid field of an input
+type FieldError struct {
+	// Field path of the field in the input json (ie: "locations[1].lat")
+	Field string
+
+	// Message description of the problem
+	Message string
+}
+
+// Error implements error
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError is returned by the Validate methods of inputs, listing all the invalid fields
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		messages = append(messages, fieldErr.Error())
+	}
+
+	return "invalid input: " + strings.Join(messages, "; ")
+}
+
+// Is returns true if target is ErrInvalidInput
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// Field returns the error of field, nil if field is valid
+func (e *ValidationError) Field(field string) *FieldError {
+	for _, fieldErr := range e.Errors {
+		if fieldErr.Field == field {
+			return fieldErr
+		}
+	}
+
+	return nil
+}
+
+// validator is implemented by the inputs having a Validate method
+type validator interface {
+	Validate() error
+}
+
+// Known values of validated fields
+var (
+	validCostingModels = []string{
+		CostingModelAuto, CostingModelBicycle, CostingModelBus, CostingModelBikeshare, CostingModelTruck,
+		CostingModelTaxi, CostingModelMotorScooter, CostingModelMotorcycle, CostingModelMultimodal, CostingModelPedestrian,
+	}
+	validUnits          = []string{"km", "kilometers", "mi", "miles"}
+	validDirectionTypes = []string{DirectionsTypeNone, DirectionsTypeManeuvers, DirectionsTypeInstructions}
+	validLocationTypes  = []string{
+		RouteInputLocationTypeBreak, RouteInputLocationTypeThrough,
+		RouteInputLocationTypeVia, RouteInputLocationTypeBreakThrough,
+	}
+	validShapeMatches  = []string{ShapeMatchEdgeWalk, ShapeMatchMapSnap, ShapeMatchWalkOrSnap}
+	validShapeFormats  = []string{"polyline6", "polyline5"}
+	validFilterActions = []string{TraceAttributesFilterActionInclude, TraceAttributesFilterActionExclude}
+)
+
+// fieldErrors accumulates the field errors of an input
+type fieldErrors []*FieldError
+
+func (errs *fieldErrors) add(field string, format string, args ...interface{}) {
+	*errs = append(*errs, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns a ValidationError of the accumulated errors, nil if none
+func (errs fieldErrors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Errors: errs}
+}
+
+// lat validates a latitude
+func (errs *fieldErrors) lat(field string, lat *float64) {
+	switch {
+	case lat == nil:
+		errs.add(field, "is required")
+	case *lat < -90 || *lat > 90:
+		errs.add(field, "must be between -90 and 90, got %g", *lat)
+	}
+}
+
+// lon validates a longitude
+func (errs *fieldErrors) lon(field string, lon *float64) {
+	switch {
+	case lon == nil:
+		errs.add(field, "is required")
+	case *lon < -180 || *lon > 180:
+		errs.add(field, "must be between -180 and 180, got %g", *lon)
+	}
+}
+
+// oneOf validates that value, if set, is one of values
+func (errs *fieldErrors) oneOf(field string, value *string, values []string) {
+	if value == nil || containsString(values, *value) {
+		return
+	}
+
+	errs.add(field, "unknown value %q, expected one of %s", *value, strings.Join(values, ", "))
+}
+
+// containsString returns true if value is in values
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// costing validates a required costing model
+func (errs *fieldErrors) costing(field string, costing *string) {
+	if costing == nil {
+		errs.add(field, "is required")
+		return
+	}
+
+	errs.oneOf(field, costing, validCostingModels)
+}
+
+// locations validates a list of at least min locations
+func (errs *fieldErrors) locations(field string, locations []*RouteLocation, min int) {
+	if len(locations) < min {
+		errs.add(field, "at least %d locations are required, got %d", min, len(locations))
+	}
+
+	for i, location := range locations {
+		locationField := fmt.Sprintf("%s[%d]", field, i)
+		if location == nil {
+			errs.add(locationField, "is required")
+			continue
+		}
+
+		errs.lat(locationField+".lat", location.Lat)
+		errs.lon(locationField+".lon", location.Lon)
+		errs.oneOf(locationField+".type", location.Type, validLocationTypes)
+	}
+}
+
+// dateTime validates an optional date time
+func (errs *fieldErrors) dateTime(field string, dateTime *DateTime) {
+	if dateTime == nil || dateTime.Type == nil {
+		return
+	}
+
+	dateTimeType := DateTimeType(*dateTime.Type)
+	if dateTimeType < DateTimeTypeCurrent || dateTimeType > DateTimeTypeInvariant {
+		errs.add(field+".type", "unknown date time type %d", dateTimeType)
+		return
+	}
+
+	if dateTimeType == DateTimeTypeCurrent {
+		return
+	}
+
+	if _, err := dateTime.Time(nil); err != nil || dateTime.Value == nil {
+		errs.add(field+".value", "must be a local date time formatted as YYYY-MM-DDThh:mm")
+	}
+}
+
+// routeOutputFormat validates that format, if set, is decoded into a RouteOutput
+func (errs *fieldErrors) routeOutputFormat(field string, format *string) {
+	switch {
+	case format == nil:
+	case *format == FormatOSRM:
+		errs.add(field, "osrm responses are not decoded by Route, use RouteOSRM")
+	case *format == FormatGPX:
+		errs.add(field, "gpx responses are not decoded by Route, export the route with RouteOutput.WriteGPX")
+	default:
+		errs.oneOf(field, format, []string{FormatJSON, FormatPBF})
+	}
+}
+
+// validateOutputFormat returns a ValidationError if the input format is not decoded into a RouteOutput
+func (input *RouteInput) validateOutputFormat() error {
+	errs := fieldErrors{}
+	errs.routeOutputFormat("format", input.Format)
+
+	return errs.err()
+}
+
+// Validate returns a ValidationError if the input is invalid
+func (input *RouteInput) Validate() error {
+	errs := fieldErrors{}
+	errs.routeOutputFormat("format", input.Format)
+	input.validate(&errs)
+
+	return errs.err()
+}
+
+// Validate returns a ValidationError if the input is invalid
+func (input *osrmRouteInput) Validate() error {
+	errs := fieldErrors{}
+	(*RouteInput)(input).validate(&errs)
+
+	return errs.err()
+}
+
+// validate adds the errors of the fields of input, except its format, to errs
+func (input *RouteInput) validate(errs *fieldErrors) {
+	errs.locations("locations", input.Locations, 2)
+	errs.costing("costing", input.Costing)
+	errs.oneOf("units", input.Units, validUnits)
+	errs.oneOf("directions_type", input.DirectionsType, validDirectionTypes)
+	errs.dateTime("date_time", input.DateTime)
+
+	if input.Alternates != nil {
+		switch {
+		case *input.Alternates < 0:
+			errs.add("alternates", "must be positive, got %d", *input.Alternates)
+		case *input.Alternates > 0 && len(input.Locations) > 2:
+			errs.add("alternates", "alternates are not supported on routes with more than 2 locations")
+		}
+	}
+
+	for i, location := range input.ExcludeLocations {
+		field := fmt.Sprintf("exclude_locations[%d]", i)
+		if location == nil {
+			errs.add(field, "is required")
+			continue
+		}
+
+		errs.lat(field+".lat", location.Lat)
+		errs.lon(field+".lon", location.Lon)
+	}
+
+	for i, ring := range input.ExcludePolygons {
+		if len(ring) < 3 {
+			errs.add(fmt.Sprintf("exclude_polygons[%d]", i), "a ring requires at least 3 coordinates, got %d", len(ring))
+		}
+	}
+}
+
+// Validate returns a ValidationError if the input is invalid
+func (input *MatrixInput) Validate() error {
+	errs := fieldErrors{}
+	errs.locations("sources", input.Sources, 1)
+	errs.locations("targets", input.Targets, 1)
+	errs.costing("costing", input.Costing)
+	errs.oneOf("units", input.Units, validUnits)
+	errs.oneOf("format", input.Format, []string{FormatJSON, FormatPBF})
+	errs.dateTime("date_time", input.DateTime)
+
+	return errs.err()
+}
+
+// Validate returns a ValidationError if the input is invalid
+func (input *IsochroneInput) Validate() error {
+	errs := fieldErrors{}
+	errs.costing("costing", input.Costing)
+	errs.dateTime("date_time", input.DateTime)
+
+	if len(input.Locations) == 0 {
+		errs.add("locations", "at least 1 location is required")
+	}
+
+	for i, location := range input.Locations {
+		field := fmt.Sprintf("locations[%d]", i)
+		if location == nil {
+			errs.add(field, "is required")
+			continue
+		}
+
+		errs.lat(field+".lat", location.Lat)
+		errs.lon(field+".lon", location.Lon)
+	}
+
+	if len(input.Contours) == 0 {
+		errs.add("contours", "at least 1 contour is required")
+	}
+
+	hasTime, hasDistance := false, false
+	for i, contour := range input.Contours {
+		field := fmt.Sprintf("contours[%d]", i)
+		switch {
+		case contour == nil || (contour.Time == nil && contour.Distance == nil):
+			errs.add(field, "time or distance is required")
+		case contour.Time != nil && contour.Distance != nil:
+			errs.add(field, "time and distance can not be both set")
+		case contour.Time != nil && *contour.Time <= 0:
+			errs.add(field+".time", "must be positive, got %g", *contour.Time)
+		case contour.Distance != nil && *contour.Distance <= 0:
+			errs.add(field+".distance", "must be positive, got %g", *contour.Distance)
+		}
+
+		if contour != nil {
+			hasTime = hasTime || contour.Time != nil
+			hasDistance = hasDistance || contour.Distance != nil
+		}
+	}
+
+	if hasTime && hasDistance {
+		errs.add("contours", "contours must all be time or all be distance based")
+	}
+
+	return errs.err()
+}
+
+// Validate returns a ValidationError if the input is invalid
+func (input *ElevationInput) Validate() error {
+	errs := fieldErrors{}
+	errs.oneOf("shape_format", input.ShapeFormat, validShapeFormats)
+
+	switch {
+	case len(input.Shape) == 0 && input.EncodedPolyline == nil:
+		errs.add("shape", "shape or encoded_polyline is required")
+	case len(input.Shape) > 0 && input.EncodedPolyline != nil:
+		errs.add("shape", "shape and encoded_polyline can not be both set")
+	}
+
+	for i, point := range input.Shape {
+		field := fmt.Sprintf("shape[%d]", i)
+		if point == nil {
+			errs.add(field, "is required")
+			continue
+		}
+
+		errs.lat(field+".lat", &point.Lat)
+		errs.lon(field+".lon", &point.Lon)
+	}
+
+	if input.ResampleDistance != nil && *input.ResampleDistance < 10 {
+		errs.add("resample_distance", "must be at least 10 meters, got %d", *input.ResampleDistance)
+	}
+
+	if input.HeightPrecision != nil && (*input.HeightPrecision < 0 || *input.HeightPrecision > 2) {
+		errs.add("height_precision", "must be 0, 1 or 2, got %d", *input.HeightPrecision)
+	}
+
+	return errs.err()
+}
+
+// Validate returns a ValidationError if the input is invalid
+func (input *LocateInput) Validate() error {
+	errs := fieldErrors{}
+	errs.locations("locations", input.Locations, 1)
+	errs.oneOf("costing", input.Costing, validCostingModels)
+
+	return errs.err()
+}
+
+// validate appends the errors of the trace input to errs
+func (input *TraceInput) validate(errs *fieldErrors) {
+	errs.costing("costing", input.Costing)
+	errs.oneOf("shape_match", input.ShapeMatch, validShapeMatches)
+	errs.oneOf("shape_format", input.ShapeFormat, validShapeFormats)
+	errs.oneOf("units", input.Units, validUnits)
+
+	switch {
+	case len(input.Shape) == 0 && input.EncodedPolyline == nil:
+		errs.add("shape", "shape or encoded_polyline is required")
+	case len(input.Shape) > 0 && input.EncodedPolyline != nil:
+		errs.add("shape", "shape and encoded_polyline can not be both set")
+	case input.EncodedPolyline == nil && len(input.Shape) < 2:
+		errs.add("shape", "at least 2 points are required, got %d", len(input.Shape))
+	}
+
+	for i, point := range input.Shape {
+		field := fmt.Sprintf("shape[%d]", i)
+		if point == nil {
+			errs.add(field, "is required")
+			continue
+		}
+
+		errs.lat(field+".lat", point.Lat)
+		errs.lon(field+".lon", point.Lon)
+	}
+}
+
+// Validate returns a ValidationError if the input is invalid
+func (input *TraceInput) Validate() error {
+	errs := fieldErrors{}
+	input.validate(&errs)
+
+	return errs.err()
+}
+
+// Validate returns a ValidationError if the input is invalid
+func (input *TraceRouteInput) Validate() error {
+	errs := fieldErrors{}
+	input.TraceInput.validate(&errs)
+	errs.oneOf("directions_type", input.DirectionsType, validDirectionTypes)
+
+	return errs.err()
+}
+
+// Validate returns a ValidationError if the input is invalid
+func (input *TraceAttributesInput) Validate() error {
+	errs := fieldErrors{}
+	input.TraceInput.validate(&errs)
+
+	if input.Filters != nil {
+		errs.oneOf("filters.action", input.Filters.Action, validFilterActions)
+		if input.Filters.Action == nil {
+			errs.add("filters.action", "is required")
+		}
+	}
+
+	return errs.err()
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRouteInputValidate(t *testing.T) {
+	input := &RouteInput{
+		Locations: []*RouteLocation{
+			{Lat: ptr.Float64(123), Lon: ptr.Float64(-4.486076)},
+			{Lat: ptr.Float64(48.45252), Lon: ptr.Float64(-4.25252)},
+			{Lat: ptr.Float64(48.40912)},
+		},
+		Costing:    ptr.String("spaceship"),
+		Alternates: ptr.Int(2),
+	}
+
+	err := input.Validate()
+
+	validationErr := &ValidationError{}
+	if !errors.As(err, &validationErr) || !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+
+	for _, field := range []string{"locations[0].lat", "locations[2].lon", "costing", "alternates"} {
+		if validationErr.Field(field) == nil {
+			t.Fatalf("expected an error for field %s, got %v", field, err)
+		}
+	}
+
+	if len(validationErr.Errors) != 4 {
+		t.Fatalf("expected 4 field errors, got %v", err)
+	}
+
+	valid := &RouteInput{
+		Locations: []*RouteLocation{
+			{Lat: ptr.Float64(48.390394), Lon: ptr.Float64(-4.486076)},
+			{Lat: ptr.Float64(48.45252), Lon: ptr.Float64(-4.25252)},
+		},
+		Costing:  ptr.String(CostingModelAuto),
+		DateTime: Now(),
+	}
+
+	if err := valid.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	valid.Format = ptr.String(FormatOSRM)
+	if err := valid.Validate(); err == nil || err.(*ValidationError).Field("format") == nil {
+		t.Fatalf("expected format error, got %v", err)
+	}
+}
+
+func TestInputsValidate(t *testing.T) {
+	isochrone := &IsochroneInput{
+		Locations: []*IsochroneInputLocation{{Lat: ptr.Float64(48.390394), Lon: ptr.Float64(-4.486076)}},
+		Costing:   ptr.String(CostingModelPedestrian),
+		Contours:  []*IsochroneInputContour{{Time: ptr.Float64(10), Distance: ptr.Float64(1)}},
+		DateTime:  &DateTime{Type: ptr.Int(1), Value: ptr.String("tomorrow")},
+	}
+
+	err := isochrone.Validate()
+	if err == nil || err.(*ValidationError).Field("contours[0]") == nil || err.(*ValidationError).Field("date_time.value") == nil {
+		t.Fatalf("expected contour and date time errors, got %v", err)
+	}
+
+	elevation := &ElevationInput{EncodedPolyline: ptr.String("_p~iF~ps|U"), Shape: []*ElevationPoint{{Lat: 1, Lon: 1}}}
+	if err := elevation.Validate(); err == nil || err.(*ValidationError).Field("shape") == nil {
+		t.Fatalf("expected shape error, got %v", err)
+	}
+
+	matrix := &MatrixInput{Targets: []*RouteLocation{{Lat: ptr.Float64(1), Lon: ptr.Float64(1)}}, Costing: ptr.String(CostingModelAuto)}
+	if err := matrix.Validate(); err == nil || err.(*ValidationError).Field("sources") == nil {
+		t.Fatalf("expected sources error, got %v", err)
+	}
+
+	locate := &LocateInput{Locations: []*RouteLocation{{Lat: ptr.Float64(1), Lon: ptr.Float64(1)}}}
+	if err := locate.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	trace := &TraceAttributesInput{
+		TraceInput: TraceInput{EncodedPolyline: ptr.String("_p~iF~ps|U"), Costing: ptr.String(CostingModelAuto)},
+		Filters:    &TraceAttributesFilters{Attributes: []string{TraceAttributesFilterEdgeNames}},
+	}
+	if err := trace.Validate(); err == nil || err.(*ValidationError).Field("filters.action") == nil {
+		t.Fatalf("expected filters action error, got %v", err)
+	}
+}
+
+func TestClientValidateInputs(t *testing.T) {
+	var calls int32
+
+	clt := getLocalTestPoolClient(
+		t,
+		&ClientConfig{Endpoint: "http://valhalla.local", ValidateInputs: true},
+		map[string]fasthttp.RequestHandler{"valhalla.local": func(ctx *fasthttp.RequestCtx) {
+			atomic.AddInt32(&calls, 1)
+			ctx.SetBodyString(`[]`)
+		}},
+	)
+
+	_, err := clt.Route(&RouteInput{Costing: ptr.String(CostingModelAuto)})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected invalid input error, got %v", err)
+	}
+
+	if atomic.LoadInt32(&calls) != 0 {
+		t.Fatal("expected invalid input not to be sent")
+	}
+
+	_, err = clt.Locate(&LocateInput{Locations: []*RouteLocation{{Lat: ptr.Float64(1), Lon: ptr.Float64(1)}}})
+	if err != nil || atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("expected valid input to be sent, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package client
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// writeXML writes the xml header and doc to w
+func writeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("unable to encode xml document: %w", err)
+	}
+
+	return enc.Flush()
+}
+
+// formatCoordinate formats a coordinate value in fixed notation, without trailing zeros
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}